@@ -3,18 +3,21 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"google.golang.org/genai"
 	"self-management-bot/config"
+	"self-management-bot/logging"
 )
 
-func GetGeminiResponse(prompt string) (string, error) {
+const geminiModel = "gemini-2.5-flash"
+
+func GetGeminiResponse(ctx context.Context, prompt string) (string, error) {
 	apiKey := config.Cfg.GeminiApiKey
 	if apiKey == "" {
 		return "", fmt.Errorf("Gemini API key is not set")
 	}
 
-	ctx := context.Background()
 	cl, err := genai.NewClient(ctx, &genai.ClientConfig{
 		APIKey:  apiKey,
 		Backend: genai.BackendGeminiAPI,
@@ -23,12 +26,16 @@ func GetGeminiResponse(prompt string) (string, error) {
 		return "", fmt.Errorf("genai client init: %w", err)
 	}
 
-	res, err := cl.Models.GenerateContent(ctx, "gemini-2.5-flash",
+	logger := logging.From(ctx).With("model", geminiModel)
+	start := time.Now()
+	res, err := cl.Models.GenerateContent(ctx, geminiModel,
 		[]*genai.Content{{Parts: []*genai.Part{{Text: prompt}}}},
 		nil)
 	if err != nil {
+		logger.Error("Gemini generate failed", "error", err, "duration", time.Since(start))
 		return "", fmt.Errorf("generate: %w", err)
 	}
+	logger.Debug("Gemini generate", "prompt_len", len(prompt), "duration", time.Since(start))
 	txt := res.Text()
 	if txt == "" {
 		return "", fmt.Errorf("empty response")
@@ -1,40 +1,87 @@
 package main
 
 import (
+	"flag"
 	"github.com/bwmarrin/discordgo"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
 	"self-management-bot/config"
 	"self-management-bot/db"
 	"self-management-bot/handler"
+	"self-management-bot/logging"
+	"self-management-bot/scheduler"
 )
 
+// fatal はエラーを記録して終了します。
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
 func main() {
-	config.LoadConfig()
-	token := config.Cfg.DiscordToken
+	migrateOnly := flag.Bool("migrate-only", false, "マイグレーションを適用して終了する")
+	migrateDown := flag.Int("migrate-down", 0, "指定した件数だけマイグレーションを巻き戻して終了する")
+	flag.Parse()
+	config.LoadEnv()
+	logCfg := config.LoadLogConfig()
+	logging.Init(logCfg.Format, logCfg.Level)
+	dbCfg := config.LoadDBConfig()
+
+	if *migrateDown > 0 {
+		if err := db.Connect(dbCfg); err != nil {
+			fatal("DB 接続失敗", err)
+		}
+		if err := db.MigrateDown(*migrateDown); err != nil {
+			fatal("マイグレーション巻き戻し失敗", err)
+		}
+		slog.Info("マイグレーション巻き戻し完了")
+		return
+	}
+
 	// Connect DB
-	if err := db.Init(); err != nil {
-		log.Fatal("❌ DB 初期化失敗:", err)
+	if err := db.Init(dbCfg); err != nil {
+		fatal("DB 初期化失敗", err)
 	}
-	log.Println("✅ DB 初期化成功")
+	slog.Info("DB 初期化成功", "driver", db.Driver)
+	if *migrateOnly {
+		return
+	}
+
+	config.LoadConfig()
+	// コマンドの実行回数・処理時間（expvar）を /debug/vars で公開する
+	if addr := config.Cfg.MetricsAddr; addr != "" {
+		go func() {
+			slog.Info("メトリクス公開", "addr", addr)
+			if err := http.ListenAndServe(addr, nil); err != nil {
+				slog.Error("メトリクスサーバ停止", "error", err)
+			}
+		}()
+	}
+	token := config.Cfg.DiscordToken
 	// session with Discord
 	dg, err := discordgo.New("Bot " + token)
 	if err != nil {
-		log.Fatal("❌ Error creating Discord session,", err)
+		fatal("Error creating Discord session", err)
 	}
 	dg.AddHandler(handler.MessageCreate)
-	log.Println("✅ Discordセッション成功")
+	dg.AddHandler(handler.InteractionCreate)
+	slog.Info("Discordセッション成功")
 	// connect with Discord
 	err = dg.Open()
 	if err != nil {
-		log.Fatal("❌ Error opening Discord connection,", err)
+		fatal("Error opening Discord connection", err)
 	}
-	log.Println("✅ Discord接続成功")
+	slog.Info("Discord接続成功")
 
 	defer dg.Close()
 	// パッチ処理
 	handler.StartResetConfirmCleaner()
-	handler.StartFixedReminderSender(dg)
+	handler.RegisterJobs(dg)
+	if err := scheduler.Start(); err != nil {
+		fatal("スケジューラ開始失敗", err)
+	}
 
-	log.Println("✅ Bot is now running... ")
+	slog.Info("Bot is now running")
 	select {}
 }
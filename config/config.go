@@ -1,38 +1,169 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config はアプリケーション全体の設定を保持します。
+// DB とログの設定は DISCORD_TOKEN がなくても（マイグレーションのみでも）使えるよう、
+// LoadDBConfig / LoadLogConfig で別に読み込みます。
 type Config struct {
 	DiscordToken string
 	GeminiApiKey string
+	// BotAdminIDs はBot管理コマンド（!broadcast, !stats など）を実行できるユーザIDです。
+	BotAdminIDs []string
+	// ManagerRoles はこの名前のロールを持つユーザを共有リストの管理者として扱います。
+	ManagerRoles []string
+	// MetricsAddr が設定されていれば、そのアドレスで /debug/vars（expvar）を公開します（例: ":8080"）。
+	MetricsAddr string
+}
+
+// LogConfig はログ出力の設定を保持します。
+type LogConfig struct {
+	Format string // "json" か "text"
+	Level  string // "debug", "info", "warn", "error"
+}
+
+// DBConfig はDB接続とコネクションプールの設定を保持します。
+type DBConfig struct {
+	// Driver は "postgres" か "sqlite" です。
+	Driver string
+	// SQLitePath は Driver が "sqlite" のときのDBファイルのパスです。
+	SQLitePath string
+
+	// DatabaseURL が設定されていれば個別の DB_* より優先します。
+	DatabaseURL string
+	Host        string
+	Port        string
+	User        string
+	Password    string
+	Name        string
+	SSLMode     string
+	SSLRootCert string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// 起動時の接続リトライ回数と初回の待ち時間（以降は倍々で伸びる）
+	ConnectRetries int
+	RetryInterval  time.Duration
 }
 
 // Cfg はロードされた設定を保持するグローバル変数です。
 var Cfg *Config
 
+// LoadEnv は.envファイルから環境変数を読み込みます（ファイルが存在しない場合もエラーにしない）。
+func LoadEnv() {
+	_ = godotenv.Load()
+}
+
 // LoadConfig は環境変数または.envファイルから設定を読み込みます。
 func LoadConfig() {
-	// .envファイルから環境変数を読み込む（ファイルが存在しない場合もエラーにしない）
-	_ = godotenv.Load()
+	LoadEnv()
 
 	token := os.Getenv("DISCORD_TOKEN")
 	if token == "" {
-		log.Fatal("環境変数 'DISCORD_TOKEN' が設定されていません。")
+		slog.Error("環境変数 'DISCORD_TOKEN' が設定されていません。")
+		os.Exit(1)
 	}
 
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
-		log.Fatal("環境変数 'GEMINI_API_KEY' が設定されていません。")
+		slog.Error("環境変数 'GEMINI_API_KEY' が設定されていません。")
+		os.Exit(1)
 	}
 
 	Cfg = &Config{
 		DiscordToken: token,
 		GeminiApiKey: apiKey,
+		BotAdminIDs:  getEnvList("BOT_ADMIN_IDS", nil),
+		ManagerRoles: getEnvList("MANAGER_ROLES", []string{"Task Manager"}),
+		MetricsAddr:  os.Getenv("METRICS_ADDR"),
+	}
+}
+
+// LoadLogConfig はログ関連の環境変数を読み込みます。
+func LoadLogConfig() LogConfig {
+	return LogConfig{
+		Format: getEnv("LOG_FORMAT", "text"),
+		Level:  getEnv("LOG_LEVEL", "info"),
+	}
+}
+
+// LoadDBConfig はDB関連の環境変数を読み込みます。
+// マイグレーションのみ実行する場合でも使えるよう、必須チェックは行いません。
+// DB_* の既定値は docker-compose の db サービスに合わせています（以前は entrypoint.sh で設定していたもの）。
+func LoadDBConfig() DBConfig {
+	return DBConfig{
+		Driver:          getEnv("DB_DRIVER", "postgres"),
+		SQLitePath:      getEnv("SQLITE_PATH", "self_management.db"),
+		DatabaseURL:     os.Getenv("DATABASE_URL"),
+		Host:            getEnv("DB_HOST", "db"),
+		Port:            getEnv("DB_PORT", "5432"),
+		User:            getEnv("DB_USER", "user"),
+		Password:        getEnv("DB_PASSWORD", "password"),
+		Name:            getEnv("DB_NAME", "self_management"),
+		SSLMode:         getEnv("DB_SSLMODE", "disable"),
+		SSLRootCert:     os.Getenv("DB_SSLROOTCERT"),
+		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 10),
+		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnectRetries:  getEnvInt("DB_CONNECT_RETRIES", 10),
+		RetryInterval:   getEnvDuration("DB_RETRY_INTERVAL", time.Second),
+	}
+}
+
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// getEnvList はカンマ区切りの環境変数を読み込みます。
+func getEnvList(key string, def []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		slog.Warn("環境変数が数値ではないため既定値を使います", "key", key, "default", def)
+		return def
+	}
+	return n
+}
+
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		slog.Warn("環境変数が時間の形式ではないため既定値を使います", "key", key, "default", def)
+		return def
 	}
+	return d
 }
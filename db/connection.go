@@ -4,24 +4,85 @@ import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
-	"os"
+	"log/slog"
+	_ "modernc.org/sqlite"
+	"self-management-bot/config"
+	"time"
 )
 
 var DB *sqlx.DB
 
-// Init connection
-func Init() error {
-	var err error
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
+// maxRetryInterval はリトライ間隔の上限です。
+const maxRetryInterval = 30 * time.Second
+
+// dsn は設定から接続文字列を組み立てます。DATABASE_URL があればそれを優先します。
+func dsn(cfg config.DBConfig) string {
+	if cfg.Driver == DriverSQLite {
+		// 外部キー制約を有効にし、書き込み競合時は待つ
+		return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", cfg.SQLitePath)
+	}
+	if cfg.DatabaseURL != "" {
+		return cfg.DatabaseURL
+	}
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Host,
+		cfg.User,
+		cfg.Password,
+		cfg.Name,
+		cfg.Port,
+		cfg.SSLMode,
 	)
-	DB, err = sqlx.Open("postgres", dsn)
+	if cfg.SSLRootCert != "" {
+		dsn += " sslrootcert=" + cfg.SSLRootCert
+	}
+	return dsn
+}
+
+// Connect DBに接続する（マイグレーションは行わない）
+// DBの起動が遅れても落ちないよう、Pingが通るまでバックオフしながら再試行します。
+func Connect(cfg config.DBConfig) error {
+	var err error
+	switch cfg.Driver {
+	case DriverPostgres, DriverSQLite:
+		Driver = cfg.Driver
+	default:
+		return fmt.Errorf("unsupported DB_DRIVER: %s", cfg.Driver)
+	}
+	DB, err = sqlx.Open(Driver, dsn(cfg))
 	if err != nil {
 		return err
 	}
-	return DB.Ping()
+	if Driver == DriverSQLite {
+		// SQLiteは書き込みが1本に限られるので、接続も1本にしてロック待ちを避ける
+		DB.SetMaxOpenConns(1)
+	} else {
+		DB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	DB.SetMaxIdleConns(cfg.MaxIdleConns)
+	DB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	interval := cfg.RetryInterval
+	for attempt := 1; ; attempt++ {
+		err = DB.Ping()
+		if err == nil {
+			return nil
+		}
+		if attempt > cfg.ConnectRetries {
+			return fmt.Errorf("ping failed after %d attempts: %w", attempt, err)
+		}
+		slog.Warn("DB接続待ち", "attempt", attempt, "max_retries", cfg.ConnectRetries, "retry_in", interval, "error", err)
+		time.Sleep(interval)
+		interval *= 2
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
+
+// Init connection and apply embedded migrations
+func Init(cfg config.DBConfig) error {
+	if err := Connect(cfg); err != nil {
+		return err
+	}
+	return MigrateUp()
 }
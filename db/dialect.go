@@ -0,0 +1,121 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Driver は接続中のDBドライバ名です。
+var Driver = DriverPostgres
+
+func init() {
+	// SQLite(modernc)も $1 形式のプレースホルダを受け付けるので、Postgresと同じ書き方に揃える
+	sqlx.BindDriver(DriverSQLite, sqlx.DOLLAR)
+}
+
+// 以下はドライバごとに異なる日付まわりのSQL式を返すヘルパーです。
+// SQLiteは日時をUTCで保存しているため、'localtime' でPostgresのセッションTZと揃えます。
+
+// Today は今日の日付を表すSQL式を返します。
+func Today() string {
+	if Driver == DriverSQLite {
+		return "date('now', 'localtime')"
+	}
+	return "CURRENT_DATE"
+}
+
+// DaysAgo は n 日前の日付を表すSQL式を返します。
+func DaysAgo(n int) string {
+	if Driver == DriverSQLite {
+		return fmt.Sprintf("date('now', 'localtime', '-%d day')", n)
+	}
+	return fmt.Sprintf("(CURRENT_DATE - %d)", n)
+}
+
+// DateOf は日時カラム col の日付部分を表すSQL式を返します。
+func DateOf(col string) string {
+	if Driver == DriverSQLite {
+		return fmt.Sprintf("date(%s, 'localtime')", col)
+	}
+	return col + "::date"
+}
+
+// Now は現在時刻を表すSQL式を返します。
+func Now() string {
+	if Driver == DriverSQLite {
+		return "CURRENT_TIMESTAMP"
+	}
+	return "NOW()"
+}
+
+// DateText は日時カラム col の日付部分を 'YYYY-MM-DD' 形式の文字列で表すSQL式を返します。
+func DateText(col string) string {
+	if Driver == DriverSQLite {
+		return fmt.Sprintf("date(%s, 'localtime')", col)
+	}
+	return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", col)
+}
+
+// DateTimeText は日時カラム col を 'YYYY-MM-DD HH:MM' 形式の文字列で表すSQL式を返します。
+func DateTimeText(col string) string {
+	if Driver == DriverSQLite {
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M', %s, 'localtime')", col)
+	}
+	return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD HH24:MI')", col)
+}
+
+// ParseDateTimeText は DateTimeText で取得した文字列をローカル時刻にします。
+func ParseDateTimeText(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+}
+
+// TimeValue は Go の時刻を、日時カラムに保存・比較するときの引数にします。
+// SQLiteは CURRENT_TIMESTAMP と同じくUTC、Postgresは NOW() と同じくローカル時刻の 'YYYY-MM-DD HH:MM:SS' です。
+func TimeValue(t time.Time) string {
+	if Driver == DriverSQLite {
+		return t.UTC().Format(time.DateTime)
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// SkipLocked は SELECT に付けて、他のトランザクションがロックしている行を飛ばす句を返します。
+// SQLiteは書き込みが1つずつなので不要です。
+func SkipLocked() string {
+	if Driver == DriverSQLite {
+		return ""
+	}
+	return " FOR UPDATE SKIP LOCKED"
+}
+
+// IsUniqueViolation は err が一意制約違反かどうかを返します。
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+	return false
+}
+
+// ILike は col に LIKE パターン pattern（プレースホルダ）が大文字小文字を区別せずに一致する条件式を返します。
+// pattern は小文字にしておきます。Postgres では ILIKE にして、title などのトライグラムインデックスが使われるようにします。
+func ILike(col, pattern string) string {
+	if Driver == DriverSQLite {
+		return fmt.Sprintf(`LOWER(%s) LIKE %s ESCAPE '\'`, col, pattern)
+	}
+	return fmt.Sprintf(`%s ILIKE %s ESCAPE '\'`, col, pattern)
+}
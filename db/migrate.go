@@ -0,0 +1,248 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationFS はバイナリに埋め込んだマイグレーションSQLです。
+// migrations/<ドライバ名>/ 以下に golang-migrate と同じ <version>_<name>.(up|down).sql 形式で置きます。
+//
+//go:embed migrations/*/*.sql
+var migrationFS embed.FS
+
+// migration は1バージョン分のup/down SQLを保持します。
+type migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// loadMigrations は接続中のドライバ向けのSQLをバージョン順に読み込みます。
+func loadMigrations() ([]migration, error) {
+	dir := path.Join("migrations", Driver)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*migration{}
+	for _, e := range entries {
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		prefix, rest, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", name, err)
+		}
+		body, err := fs.ReadFile(migrationFS, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{Version: version, Name: strings.TrimSuffix(rest, "."+direction+".sql")}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d has no up file", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// migrationLockID は Postgres でマイグレーション中に取る advisory lock のキーです。
+const migrationLockID = 20250101
+
+// step は適用する1件分のSQLと、適用後のバージョンです。
+type step struct {
+	Label   string // ログとエラーに使う "<version>_<name> up" など
+	SQL     string
+	Version int
+}
+
+// ensureVersionTable はバージョン管理テーブルを作成します。
+// 既存環境で golang-migrate が作ったテーブルをそのまま引き継げるよう同じ構造にしています。
+func ensureVersionTable(ctx context.Context, q sqlx.ExecerContext) error {
+	_, err := q.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty BOOLEAN NOT NULL
+	)`)
+	return err
+}
+
+// currentVersion は適用済みのバージョンを返します。未適用なら0です。
+// dirty は golang-migrate が適用の途中で失敗したときに立てるフラグです。ここでは1件ずつトランザクションで
+// 適用するので立てませんが、golang-migrate から引き継いだテーブルで立っていれば手で直すまで適用を止めます。
+func currentVersion(ctx context.Context, q sqlx.QueryerContext) (int, bool, error) {
+	var rows []struct {
+		Version int  `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+	if err := sqlx.SelectContext(ctx, q, &rows, `SELECT version, dirty FROM schema_migrations LIMIT 1`); err != nil {
+		return 0, false, err
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Version, rows[0].Dirty, nil
+}
+
+// runMigration は1件のSQLを実行し、バージョンを version に更新します。
+func runMigration(ctx context.Context, q sqlx.ExecerContext, sql string, version int) error {
+	if _, err := q.ExecContext(ctx, sql); err != nil {
+		return err
+	}
+	if _, err := q.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if version > 0 {
+		if _, err := q.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrate は他のプロセスと同時に実行しないようロックを取り、適用済みのバージョンを読んでから
+// plan が返した steps を順に適用します。
+// Postgres では pg_advisory_lock を取ったうえで1件ずつトランザクションで適用します。
+// SQLite では BEGIN IMMEDIATE で書き込みロックを取り、全件を1つのトランザクションで適用します。
+func migrate(plan func(current int) ([]step, error)) (err error) {
+	ctx := context.Background()
+	conn, err := DB.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if Driver == DriverSQLite {
+		if _, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				conn.ExecContext(ctx, `ROLLBACK`)
+			}
+		}()
+	} else {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	current, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema_migrations is dirty at version %d", current)
+	}
+	steps, err := plan(current)
+	if err != nil {
+		return err
+	}
+	for _, s := range steps {
+		if err := applyStep(ctx, conn, s); err != nil {
+			return fmt.Errorf("migration %s: %w", s.Label, err)
+		}
+		slog.Info("マイグレーション", "step", s.Label, "version", s.Version)
+	}
+	if Driver == DriverSQLite {
+		_, err = conn.ExecContext(ctx, `COMMIT`)
+	}
+	return err
+}
+
+// applyStep は1件を適用します。SQLite ではすでに migrate がトランザクションを始めています。
+func applyStep(ctx context.Context, conn *sqlx.Conn, s step) error {
+	if Driver == DriverSQLite {
+		return runMigration(ctx, conn, s.SQL, s.Version)
+	}
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := runMigration(ctx, tx, s.SQL, s.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrateUp は未適用のマイグレーションをすべて適用します。
+func MigrateUp() error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	return migrate(func(current int) ([]step, error) {
+		var steps []step
+		for _, m := range migrations {
+			if m.Version <= current {
+				continue
+			}
+			steps = append(steps, step{Label: fmt.Sprintf("%d_%s up", m.Version, m.Name), SQL: m.Up, Version: m.Version})
+		}
+		return steps, nil
+	})
+}
+
+// MigrateDown は適用済みのマイグレーションを n 件だけ巻き戻します。
+func MigrateDown(n int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	return migrate(func(current int) ([]step, error) {
+		var steps []step
+		for i := len(migrations) - 1; i >= 0 && len(steps) < n; i-- {
+			m := migrations[i]
+			if m.Version > current {
+				continue
+			}
+			if m.Down == "" {
+				return nil, fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+			}
+			prev := 0
+			if i > 0 {
+				prev = migrations[i-1].Version
+			}
+			steps = append(steps, step{Label: fmt.Sprintf("%d_%s down", m.Version, m.Name), SQL: m.Down, Version: prev})
+		}
+		return steps, nil
+	})
+}
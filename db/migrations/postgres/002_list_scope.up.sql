@@ -0,0 +1,16 @@
+-- 個人リスト(personal)とチャンネル共有リスト(channel)を区別する
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS scope TEXT NOT NULL DEFAULT 'personal';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS scope_id TEXT NOT NULL DEFAULT '';   -- channelならチャンネルID
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS guild_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_by TEXT NOT NULL DEFAULT '';
+UPDATE tasks SET created_by = user_id WHERE created_by = '';
+CREATE INDEX IF NOT EXISTS idx_tasks_scope ON tasks (scope, scope_id);
+
+-- チャンネルに紐づけた共有リストモード（有効なチャンネルでは !add 等が共有リストに対して動く）
+CREATE TABLE IF NOT EXISTS channel_lists (
+    channel_id TEXT PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
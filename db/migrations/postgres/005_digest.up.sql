@@ -0,0 +1,9 @@
+-- デイリーダイジェストに参加するメンバー
+CREATE TABLE IF NOT EXISTS digest_members (
+    guild_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (guild_id, user_id)
+);
+-- ダイジェストにLLMの応援メッセージを付けるか
+ALTER TABLE guild_settings ADD COLUMN IF NOT EXISTS digest_pep_talk BOOLEAN;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_title_trgm;
+DROP INDEX IF EXISTS idx_tasks_title_fts;
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,8 @@
+-- 完了日時（!search で表示する）。既存の完了タスクは作成日時で埋める
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+UPDATE tasks SET completed_at = created_at WHERE status = 'completed' AND completed_at IS NULL;
+
+-- !search 用の全文検索インデックス。日本語は空白で単語に分かれないため、部分一致はトライグラムで拾う
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_tasks_title_fts ON tasks USING GIN (to_tsvector('simple', title));
+CREATE INDEX IF NOT EXISTS idx_tasks_title_trgm ON tasks USING GIN (title gin_trgm_ops);
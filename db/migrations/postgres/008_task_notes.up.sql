@@ -0,0 +1,13 @@
+-- タスクのメモ（!note で追記する。1行ずつ追記した日時を持つ）
+CREATE TABLE IF NOT EXISTS task_notes (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,          -- 書いたユーザ
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_task_notes_task_id ON task_notes (task_id);
+
+-- !search でメモも検索する
+CREATE INDEX IF NOT EXISTS idx_task_notes_body_fts ON task_notes USING GIN (to_tsvector('simple', body));
+CREATE INDEX IF NOT EXISTS idx_task_notes_body_trgm ON task_notes USING GIN (body gin_trgm_ops);
@@ -0,0 +1,5 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check;
+ALTER TABLE tasks ALTER COLUMN status SET DEFAULT 'pending';
+UPDATE tasks SET status = 'completed' WHERE status IN ('done', 'cancelled');
+UPDATE tasks SET status = 'pending' WHERE status <> 'completed';
@@ -0,0 +1,8 @@
+-- タスクの状態を todo / in_progress / blocked / waiting / done / cancelled に整理する
+UPDATE tasks SET status = 'todo' WHERE status = 'pending';
+UPDATE tasks SET status = 'done' WHERE status = 'completed';
+ALTER TABLE tasks ALTER COLUMN status SET DEFAULT 'todo';
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_check
+    CHECK (status IN ('todo', 'in_progress', 'blocked', 'waiting', 'done', 'cancelled'));
+-- blocked / waiting にした理由（!block <理由>）
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- タスクの状態変更の履歴（!done / !start / !block / !cancel / !reopen などを1件ずつ記録する）
+CREATE TABLE IF NOT EXISTS task_events (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,          -- 変更したユーザ
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events (task_id);
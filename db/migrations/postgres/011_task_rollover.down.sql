@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS defer_count;
+ALTER TABLE tasks DROP COLUMN IF EXISTS deferred_until;
+ALTER TABLE tasks DROP COLUMN IF EXISTS rolled_over_on;
+ALTER TABLE tasks DROP COLUMN IF EXISTS carry_over_days;
@@ -0,0 +1,3 @@
+-- !snooze で非表示にしたタスクは、この日になるまで一覧・リマインドに出さない（再表示を通知したら NULL に戻す）
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS snoozed_until DATE;
+CREATE INDEX IF NOT EXISTS idx_tasks_snoozed_until ON tasks (snoozed_until) WHERE snoozed_until IS NOT NULL;
@@ -0,0 +1,10 @@
+-- タスクごとのリマインド（!remind）。送れたら sent_at を記録する。送信中のロックと失敗したときの再試行は 019_reminder_retry の列で行う
+CREATE TABLE IF NOT EXISTS reminders (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,          -- DMを送るユーザ
+    remind_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_reminders_due ON reminders (remind_at) WHERE sent_at IS NULL;
@@ -0,0 +1,11 @@
+-- 定期実行するジョブ（scheduler パッケージ）。複数のプロセスが動いていても、1回の実行は1つのプロセスだけが取る
+CREATE TABLE IF NOT EXISTS jobs (
+    name TEXT PRIMARY KEY,
+    schedule TEXT NOT NULL,         -- cron式（分 時 日 月 曜日）
+    next_run_at TIMESTAMP NOT NULL,
+    locked_by TEXT,                 -- 実行中のプロセス
+    locked_until TIMESTAMP,         -- この時刻を過ぎたら、実行中のプロセスが落ちたものとして他のプロセスが取れる
+    attempts INTEGER NOT NULL DEFAULT 0, -- 今回の実行の試行回数（成功したら0に戻す）
+    last_run_at TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT ''
+);
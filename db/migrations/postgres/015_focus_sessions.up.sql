@@ -0,0 +1,14 @@
+-- 集中（ポモドーロ）と休憩のタイマー（!focus / !break）
+CREATE TABLE IF NOT EXISTS focus_sessions (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER REFERENCES tasks(id) ON DELETE CASCADE, -- 休憩は NULL
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('focus', 'break')),
+    minutes INTEGER NOT NULL,       -- 予定の分数。途中で止めたら実際に集中した分数にする
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP              -- 終了を通知した・止めた時刻。NULL なら実行中
+);
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_running ON focus_sessions (ends_at) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_task_id ON focus_sessions (task_id);
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_user_id ON focus_sessions (user_id, started_at);
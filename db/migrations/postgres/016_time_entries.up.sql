@@ -0,0 +1,14 @@
+-- タスクに実際にかけた時間（!track / !timesheet）
+CREATE TABLE IF NOT EXISTS time_entries (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    minutes INTEGER NOT NULL DEFAULT 0, -- 計測中は 0。止めたとき・手入力で記録する
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP,                 -- NULL なら計測中
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- 計測中の記録は1人1件まで
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries (user_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_id ON time_entries (user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries (task_id);
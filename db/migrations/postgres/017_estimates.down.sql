@@ -0,0 +1,2 @@
+ALTER TABLE user_settings DROP COLUMN IF EXISTS available_minutes;
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimate_minutes;
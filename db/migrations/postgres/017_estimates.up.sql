@@ -0,0 +1,4 @@
+-- タスクの見積もり（!add の est:2h）。NULL なら見積もりなし
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_minutes INTEGER;
+-- 1日に使える時間（!hours）。NULL なら既定の8時間
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS available_minutes INTEGER;
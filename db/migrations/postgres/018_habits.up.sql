@@ -0,0 +1,15 @@
+-- 習慣（!habit）。タスクと違い毎日チェックして連続日数を数える
+CREATE TABLE IF NOT EXISTS habits (
+    id SERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+-- 習慣をやった日（1日1回）
+CREATE TABLE IF NOT EXISTS habit_checkins (
+    habit_id INTEGER NOT NULL REFERENCES habits(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (habit_id, day)
+);
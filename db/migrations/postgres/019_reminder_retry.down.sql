@@ -0,0 +1,3 @@
+ALTER TABLE reminders DROP COLUMN IF EXISTS failed_at;
+ALTER TABLE reminders DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE reminders DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,5 @@
+-- 送信に失敗したリマインドの再試行（!remind）。取ったプロセスは locked_until まで送信中とし、
+-- 失敗したら locked_until を次に試す時刻にする。attempts が上限に達したら failed_at を記録してあきらめる
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_focus_sessions_unnotified;
+ALTER TABLE focus_sessions DROP COLUMN IF EXISTS failed_at;
+ALTER TABLE focus_sessions DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE focus_sessions DROP COLUMN IF EXISTS attempts;
+ALTER TABLE focus_sessions DROP COLUMN IF EXISTS notified_at;
@@ -0,0 +1,2 @@
+-- !search でメモの部分一致（ILIKE）もトライグラムのインデックスで探す
+CREATE INDEX IF NOT EXISTS idx_task_notes_body_trgm ON task_notes USING GIN (body gin_trgm_ops);
@@ -0,0 +1,2 @@
+-- SQLite の status の既定値の修正に対応するもの。Postgres は 009 で DEFAULT 'todo' にしているので何もしない
+SELECT 1;
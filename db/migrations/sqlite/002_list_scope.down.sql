@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS channel_lists;
+DROP INDEX IF EXISTS idx_tasks_scope;
+DELETE FROM tasks WHERE scope <> 'personal';
+ALTER TABLE tasks DROP COLUMN completed_by;
+ALTER TABLE tasks DROP COLUMN created_by;
+ALTER TABLE tasks DROP COLUMN guild_id;
+ALTER TABLE tasks DROP COLUMN scope_id;
+ALTER TABLE tasks DROP COLUMN scope;
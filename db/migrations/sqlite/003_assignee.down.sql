@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_created_by;
+ALTER TABLE tasks DROP COLUMN assignee_id;
@@ -0,0 +1,3 @@
+-- 他のユーザに割り当てたタスク。created_by が依頼者、assignee_id が担当者（割り当てなしは空文字）
+ALTER TABLE tasks ADD COLUMN assignee_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_tasks_created_by ON tasks (created_by);
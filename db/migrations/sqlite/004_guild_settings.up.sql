@@ -0,0 +1,13 @@
+-- サーバー/チャンネルごとのBot設定
+-- channel_id = '' の行がサーバー全体の設定、それ以外はチャンネル単位の上書き（NULLの項目は上位の設定を使う）
+CREATE TABLE IF NOT EXISTS guild_settings (
+    guild_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    prefix TEXT,                    -- コマンドの接頭辞（例: '!'）
+    digest_channel_id TEXT,         -- デイリーダイジェストの投稿先
+    reply_mode TEXT,                -- 'channel': 実行したチャンネルに返信 / 'dm': DMで返信
+    language TEXT,                  -- 既定の言語（'ja', 'en'）
+    chat_enabled BOOLEAN,           -- !chat を許可するか
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guild_id, channel_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE guild_settings DROP COLUMN digest_pep_talk;
+DROP TABLE IF EXISTS digest_members;
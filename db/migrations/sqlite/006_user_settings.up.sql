@@ -0,0 +1,7 @@
+-- ユーザごとの設定
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id TEXT PRIMARY KEY,
+    language TEXT,                  -- !lang で選んだ言語（NULLなら未設定）
+    discord_locale TEXT,            -- インタラクションから得たDiscordの言語（'ja', 'en'）
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- 完了日時（!search で表示する）。既存の完了タスクは作成日時で埋める
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP;
+UPDATE tasks SET completed_at = created_at WHERE status = 'completed' AND completed_at IS NULL;
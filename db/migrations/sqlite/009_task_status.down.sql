@@ -0,0 +1,6 @@
+ALTER TABLE tasks DROP COLUMN status_reason;
+DROP TRIGGER IF EXISTS tasks_status_default;
+DROP TRIGGER IF EXISTS tasks_status_check_update;
+DROP TRIGGER IF EXISTS tasks_status_check_insert;
+UPDATE tasks SET status = 'completed' WHERE status IN ('done', 'cancelled');
+UPDATE tasks SET status = 'pending' WHERE status <> 'completed';
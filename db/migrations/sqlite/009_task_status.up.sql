@@ -0,0 +1,22 @@
+-- タスクの状態を todo / in_progress / blocked / waiting / done / cancelled に整理する
+-- SQLiteは既存テーブルにCHECK制約を追加できないため、トリガーで同じ制約をかける
+UPDATE tasks SET status = 'todo' WHERE status = 'pending';
+UPDATE tasks SET status = 'done' WHERE status = 'completed';
+-- 既定値も変えられないため、status を省いた INSERT（001 の既定値 'pending'）は直後に 'todo' にする（Postgres の DEFAULT 'todo' に相当）
+CREATE TRIGGER IF NOT EXISTS tasks_status_check_insert BEFORE INSERT ON tasks
+WHEN NEW.status NOT IN ('todo', 'in_progress', 'blocked', 'waiting', 'done', 'cancelled', 'pending')
+BEGIN
+    SELECT RAISE(ABORT, 'invalid task status');
+END;
+CREATE TRIGGER IF NOT EXISTS tasks_status_default AFTER INSERT ON tasks
+WHEN NEW.status = 'pending'
+BEGIN
+    UPDATE tasks SET status = 'todo' WHERE id = NEW.id;
+END;
+CREATE TRIGGER IF NOT EXISTS tasks_status_check_update BEFORE UPDATE OF status ON tasks
+WHEN NEW.status NOT IN ('todo', 'in_progress', 'blocked', 'waiting', 'done', 'cancelled')
+BEGIN
+    SELECT RAISE(ABORT, 'invalid task status');
+END;
+-- blocked / waiting にした理由（!block <理由>）
+ALTER TABLE tasks ADD COLUMN status_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP COLUMN defer_count;
+ALTER TABLE tasks DROP COLUMN deferred_until;
+ALTER TABLE tasks DROP COLUMN rolled_over_on;
+ALTER TABLE tasks DROP COLUMN carry_over_days;
@@ -0,0 +1,7 @@
+-- 日をまたいで持ち越した日数（毎日の繰り越し処理で、終わっていないタスクを1日ずつ数える）
+ALTER TABLE tasks ADD COLUMN carry_over_days INTEGER NOT NULL DEFAULT 0;
+-- 最後に繰り越し処理で数えた日（'YYYY-MM-DD'。同じ日に2回数えないため）
+ALTER TABLE tasks ADD COLUMN rolled_over_on DATE;
+-- !defer で後回しにした日（'YYYY-MM-DD'）。その日までは持ち越しとして数えない
+ALTER TABLE tasks ADD COLUMN deferred_until DATE;
+ALTER TABLE tasks ADD COLUMN defer_count INTEGER NOT NULL DEFAULT 0;
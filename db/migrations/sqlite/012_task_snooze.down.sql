@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_snoozed_until;
+ALTER TABLE tasks DROP COLUMN snoozed_until;
@@ -0,0 +1,3 @@
+-- !snooze で非表示にしたタスクは、この日（'YYYY-MM-DD'）になるまで一覧・リマインドに出さない（再表示を通知したら NULL に戻す）
+ALTER TABLE tasks ADD COLUMN snoozed_until DATE;
+CREATE INDEX IF NOT EXISTS idx_tasks_snoozed_until ON tasks (snoozed_until) WHERE snoozed_until IS NOT NULL;
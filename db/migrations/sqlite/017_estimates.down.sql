@@ -0,0 +1,2 @@
+ALTER TABLE user_settings DROP COLUMN available_minutes;
+ALTER TABLE tasks DROP COLUMN estimate_minutes;
@@ -0,0 +1,3 @@
+ALTER TABLE reminders DROP COLUMN failed_at;
+ALTER TABLE reminders DROP COLUMN locked_until;
+ALTER TABLE reminders DROP COLUMN attempts;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_focus_sessions_unnotified;
+ALTER TABLE focus_sessions DROP COLUMN failed_at;
+ALTER TABLE focus_sessions DROP COLUMN locked_until;
+ALTER TABLE focus_sessions DROP COLUMN attempts;
+ALTER TABLE focus_sessions DROP COLUMN notified_at;
@@ -0,0 +1,9 @@
+-- タイマー終了の通知（!focus / !break）の再試行。終わったタイマーは ended_at を記録したうえで locked_until まで通知中とし、
+-- 送れたら notified_at を記録する。失敗したら locked_until を次に試す時刻にし、attempts が上限に達したら failed_at を記録してあきらめる
+ALTER TABLE focus_sessions ADD COLUMN notified_at TIMESTAMP; -- 通知した時刻（途中で止めたタイマーは止めた時刻）
+ALTER TABLE focus_sessions ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE focus_sessions ADD COLUMN locked_until TIMESTAMP;
+ALTER TABLE focus_sessions ADD COLUMN failed_at TIMESTAMP;
+-- これまでに終わったタイマーは通知済みとする
+UPDATE focus_sessions SET notified_at = ended_at WHERE ended_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_unnotified ON focus_sessions (ends_at) WHERE notified_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_focus_sessions_running_user;
@@ -0,0 +1,5 @@
+-- 実行中のタイマーは1人1件まで（!focus / !break を続けて送っても2つ始まらないようにする）
+-- すでに複数動いていれば、新しいもの以外を始めた時刻で止める
+UPDATE focus_sessions SET ended_at = started_at, notified_at = started_at
+WHERE ended_at IS NULL AND id <> (SELECT MAX(f.id) FROM focus_sessions f WHERE f.user_id = focus_sessions.user_id AND f.ended_at IS NULL);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_focus_sessions_running_user ON focus_sessions (user_id) WHERE ended_at IS NULL;
@@ -0,0 +1,2 @@
+-- Postgres のメモのトライグラムインデックスに対応するもの。SQLite の部分一致はインデックスを使えないので何もしない
+SELECT 1;
@@ -0,0 +1,2 @@
+-- 009 の既定値の扱いは 009 の down で戻すので、ここでは何もしない
+SELECT 1;
@@ -0,0 +1,13 @@
+-- 009 を以前の内容で適用済みのDB向け。status を省いた INSERT（既定値 'pending'）がトリガーで弾かれないよう、
+-- 009 と同じく 'pending' を受け付けて直後に 'todo' にする
+DROP TRIGGER IF EXISTS tasks_status_check_insert;
+CREATE TRIGGER tasks_status_check_insert BEFORE INSERT ON tasks
+WHEN NEW.status NOT IN ('todo', 'in_progress', 'blocked', 'waiting', 'done', 'cancelled', 'pending')
+BEGIN
+    SELECT RAISE(ABORT, 'invalid task status');
+END;
+CREATE TRIGGER IF NOT EXISTS tasks_status_default AFTER INSERT ON tasks
+WHEN NEW.status = 'pending'
+BEGIN
+    UPDATE tasks SET status = 'todo' WHERE id = NEW.id;
+END;
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/service"
+	"strings"
+)
+
+// statsLimit は !stats で表示するユーザ数の上限です。
+const statsLimit = 30
+
+// HandleBroadcast はタスクを登録したことのある全ユーザにお知らせをDMします。
+func HandleBroadcast(ctx *Context) {
+	userIDs, err := service.GetBroadcastTargets(ctx.Ctx)
+	if err != nil {
+		ctx.Reply(ctx.T("broadcast.fetch_failed"))
+		return
+	}
+	sent := 0
+	for _, userID := range userIDs {
+		header := i18n.T(service.UserLanguage(ctx.Ctx, userID, i18n.Default), "broadcast.header")
+		if err := sendDM(ctx.Session, userID, header+ctx.Raw); err != nil {
+			logging.From(ctx.Ctx).Warn("お知らせ送信失敗", "target_user_id", userID, "error", err)
+			continue
+		}
+		sent++
+	}
+	ctx.Reply(ctx.T("broadcast.sent", sent, len(userIDs)))
+}
+
+// HandleStats は全ユーザのタスク集計を表示します。
+func HandleStats(ctx *Context) {
+	stats, err := service.GetUserStatsService(ctx.Ctx)
+	if err != nil {
+		ctx.Reply(ctx.T("stats.failed"))
+		return
+	}
+	if len(stats) == 0 {
+		ctx.Reply(ctx.T("stats.empty"))
+		return
+	}
+	var msg strings.Builder
+	msg.WriteString(ctx.T("stats.header", len(stats)) + "```")
+	msg.WriteString(ctx.T("stats.columns"))
+	for i, st := range stats {
+		if i >= statsLimit {
+			msg.WriteString(ctx.T("stats.more", len(stats)-statsLimit))
+			break
+		}
+		msg.WriteString(fmt.Sprintf("%s | %d | %d | %d\n",
+			displayName(ctx.Session, ctx.Message.GuildID, st.UserID), st.Pending, st.Completed, st.CompletedToday))
+	}
+	msg.WriteString("```")
+	ctx.Reply(msg.String())
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"self-management-bot/i18n"
+	"self-management-bot/service"
+	"strings"
+)
+
+// channelMentionPattern はチャンネルメンション（<#ID>）です。
+var channelMentionPattern = regexp.MustCompile(`^<#(\d+)>$`)
+
+func registerConfigCommands() {
+	router.Register(&Command{
+		Name: "config", Category: "category.config",
+		Handler: HandleConfigShow,
+	})
+	for _, key := range []string{"prefix", "digest", "reply", "lang", "chat", "peptalk"} {
+		router.Register(&Command{
+			Name: "config " + key, Category: "category.config",
+			Permission: PermManager,
+			Args:       []ArgSpec{{Name: "arg.value", Kind: ArgText, Required: true}},
+			Handler:    HandleConfigSet,
+		})
+	}
+	router.Register(&Command{
+		Name: "config channel", Category: "category.config",
+		Permission: PermManager,
+		Args: []ArgSpec{
+			{Name: "arg.key", Kind: ArgText, Required: true},
+			{Name: "arg.value", Kind: ArgText, Required: true},
+		},
+		Handler: HandleConfigChannel,
+	})
+	router.Register(&Command{
+		Name: "lang", Category: "category.config",
+		Args:    []ArgSpec{{Name: "arg.value", Kind: ArgText}},
+		Handler: HandleLang,
+	})
+	router.Register(&Command{
+		Name: "hours", Category: "category.config",
+		Args:    []ArgSpec{{Name: "arg.value", Kind: ArgText}},
+		Handler: HandleHours,
+	})
+}
+
+// HandleConfigShow は実効設定を表示します。
+func HandleConfigShow(ctx *Context) {
+	if ctx.Message.GuildID == "" {
+		ctx.Reply(ctx.T("config.guild_only"))
+		return
+	}
+	st := ctx.Settings
+	digest := ctx.T("config.unset")
+	if st.DigestChannelID != "" {
+		digest = fmt.Sprintf("<#%s>", st.DigestChannelID)
+	}
+	chat := "on"
+	if !st.ChatEnabled {
+		chat = "off"
+	}
+	peptalk := "off"
+	if st.DigestPepTalk {
+		peptalk = "on"
+	}
+	ctx.Reply(ctx.T("config.show", st.Prefix, digest, peptalk, st.ReplyMode, st.Language, chat))
+}
+
+// HandleConfigSet はサーバー全体の設定を変更します。
+func HandleConfigSet(ctx *Context) {
+	key := strings.TrimPrefix(ctx.Command.Name, "config ")
+	updateSetting(ctx, "", key, ctx.Args[0])
+}
+
+// HandleConfigChannel はこのチャンネルだけの設定を変更します。
+func HandleConfigChannel(ctx *Context) {
+	updateSetting(ctx, ctx.Message.ChannelID, ctx.Args[0], ctx.Args[1])
+}
+
+func updateSetting(ctx *Context, channelID, key, value string) {
+	if key == "digest" {
+		if value == "here" {
+			value = ctx.Message.ChannelID
+		} else if match := channelMentionPattern.FindStringSubmatch(value); match != nil {
+			value = match[1]
+		} else if value != "off" && value != "reset" {
+			ctx.Reply(ctx.T("config.digest_invalid"))
+			return
+		}
+	}
+	if err := service.UpdateSetting(ctx.Ctx, ctx.Message.GuildID, channelID, key, strings.ToLower(value)); err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("config.updated"))
+}
+
+// HandleLang は自分の表示言語を表示・変更します。
+func HandleLang(ctx *Context) {
+	if len(ctx.Args) == 0 {
+		ctx.Reply(ctx.T("lang.current", ctx.Lang, strings.Join(i18n.Supported(), ", ")))
+		return
+	}
+	value := strings.ToLower(ctx.Args[0])
+	if err := service.SetUserLanguage(ctx.Ctx, ctx.UserID(), value); err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	if value == "reset" {
+		ctx.Lang = service.UserLanguage(ctx.Ctx, ctx.UserID(), ctx.Settings.Language)
+		ctx.Reply(ctx.T("lang.reset"))
+		return
+	}
+	// 変更後の言語で返信する
+	ctx.Lang = value
+	ctx.Reply(ctx.T("lang.updated", value))
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"self-management-bot/service"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// snoozeOffWords はスヌーズ・リマインドを解除する語です。
+var snoozeOffWords = map[string]bool{"off": true, "解除": true}
+
+// HandleSnooze はタスクを指定日まで一覧・リマインドから隠します（例: !snooze 12 3d、!snooze 12 2026-11-01）。
+// 日付の代わりに off を指定するとスヌーズを解除します。
+func HandleSnooze(ctx *Context) {
+	until := ""
+	if !snoozeOffWords[strings.ToLower(ctx.Args[1])] {
+		var err error
+		until, err = service.ParseFutureDate(ctx.Args[1], time.Now())
+		if err != nil {
+			ctx.ReplyError(err)
+			return
+		}
+	}
+	task, err := service.SnoozeTaskService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0), until)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	if until == "" {
+		ctx.Reply(ctx.T("snooze.cleared", task.ID, task.Title))
+		return
+	}
+	ctx.Reply(ctx.T("snooze.success", task.ID, task.Title, until))
+}
+
+// HandleDefer はタスクをまとめて後回しにします。
+// 最後の引数が日付（tomorrow / 明日 / YYYY-MM-DD）、それより前がタスクID（例: !defer 12 #15 tomorrow）です。
+func HandleDefer(ctx *Context) {
+	last := len(ctx.Args) - 1
+	var taskIDs []int
+	for _, arg := range ctx.Args[:last] {
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			ctx.Reply(ctx.T("common.not_number"))
+			return
+		}
+		taskIDs = append(taskIDs, id)
+	}
+	until, err := service.ParseFutureDate(ctx.Args[last], time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	tasks, err := service.DeferTasksService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, taskIDs, until)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	var msg strings.Builder
+	msg.WriteString(ctx.T("defer.success", len(tasks), until))
+	for _, task := range tasks {
+		msg.WriteString(fmt.Sprintf("#%d %s\n", task.ID, task.Title))
+	}
+	msg.WriteString("```")
+	ctx.Reply(msg.String())
+}
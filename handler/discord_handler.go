@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
 	"self-management-bot/service"
-	"strconv"
 	"strings"
 	"time"
 
@@ -26,11 +30,260 @@ var priorityEmoji = map[int]string{
 	4: "🔵", // P4
 }
 
-func replyToUser(s *discordgo.Session, chID, userID, message string) {
-	_, err := s.ChannelMessageSend(chID, fmt.Sprintf("<@%s>\n%s", userID, message))
+// statusEmoji は一覧でタスクの状態を表す絵文字です。
+var statusEmoji = map[string]string{
+	repository.StatusTodo:       "⌛️",
+	repository.StatusInProgress: "🏃",
+	repository.StatusBlocked:    "🚧",
+	repository.StatusWaiting:    "⏳",
+	repository.StatusDone:       "✅",
+	repository.StatusCancelled:  "🚫",
+}
+
+// sendDM はユーザにDMを送信します。
+func sendDM(s *discordgo.Session, userID, message string) error {
+	channel, err := s.UserChannelCreate(userID)
 	if err != nil {
-		fmt.Printf("⚠️ Discord送信エラー: %v\n", err)
+		return fmt.Errorf("DMチャンネル取得失敗: %w", err)
 	}
+	return sendMessage(s, channel.ID, "", message, nil)
+}
+
+// router は全コマンドの登録先です。
+var router = NewRouter()
+
+func init() {
+	router.Use(Recover, Logging, RateLimit(5, 10*time.Second), Authorize, Metrics)
+
+	router.Register(&Command{
+		Name: "add", Category: "category.tasks",
+		Scoped:  true,
+		Args:    []ArgSpec{{Name: "arg.title", Kind: ArgText, Required: true}},
+		Handler: HandleAdd,
+	})
+	router.Register(&Command{
+		Name: "assigned", Category: "category.tasks",
+		Handler: HandleAssigned,
+	})
+	router.Register(&Command{
+		Name: "list", Category: "category.tasks",
+		Scoped:  true,
+		Handler: HandleList,
+	})
+	router.Register(&Command{
+		Name: "search", Category: "category.tasks",
+		Scoped:  true,
+		Args:    []ArgSpec{{Name: "arg.keyword", Kind: ArgText, Required: true}},
+		Handler: HandleSearch,
+	})
+	router.Register(&Command{
+		Name: "show", Category: "category.tasks",
+		Args:    []ArgSpec{{Name: "arg.task_id", Kind: ArgID, Required: true}},
+		Handler: HandleShow,
+	})
+	router.Register(&Command{
+		Name: "note", Category: "category.tasks",
+		Args: []ArgSpec{
+			{Name: "arg.task_id", Kind: ArgID, Required: true},
+			{Name: "arg.note", Kind: ArgText, Required: true},
+		},
+		Handler: HandleNote,
+	})
+	router.Register(&Command{
+		Name: "done", Category: "category.tasks",
+		Scoped:  true,
+		Args:    []ArgSpec{{Name: "arg.number", Kind: ArgInt, Required: true}},
+		Handler: HandleComplete,
+	})
+	router.Register(&Command{
+		Name: "start", Category: "category.tasks",
+		Scoped:  true,
+		Args:    []ArgSpec{{Name: "arg.number", Kind: ArgInt, Required: true}},
+		Handler: HandleStart,
+	})
+	router.Register(&Command{
+		Name: "block", Category: "category.tasks",
+		Scoped: true,
+		Args: []ArgSpec{
+			{Name: "arg.number", Kind: ArgInt, Required: true},
+			{Name: "arg.reason", Kind: ArgText, Required: true},
+		},
+		Handler: HandleBlock,
+	})
+	router.Register(&Command{
+		Name: "wait", Category: "category.tasks",
+		Scoped: true,
+		Args: []ArgSpec{
+			{Name: "arg.number", Kind: ArgInt, Required: true},
+			{Name: "arg.reason", Kind: ArgText},
+		},
+		Handler: HandleWait,
+	})
+	router.Register(&Command{
+		Name: "cancel", Category: "category.tasks",
+		Scoped:  true,
+		Args:    []ArgSpec{{Name: "arg.number", Kind: ArgInt, Required: true}},
+		Handler: HandleCancel,
+	})
+	router.Register(&Command{
+		Name: "reopen", Category: "category.tasks",
+		Args:    []ArgSpec{{Name: "arg.task_id", Kind: ArgID, Required: true}},
+		Handler: HandleReopen,
+	})
+	router.Register(&Command{
+		Name: "defer", Category: "category.tasks",
+		Args: []ArgSpec{
+			{Name: "arg.task_id", Kind: ArgID, Required: true},
+			{Name: "arg.date", Kind: ArgText, Required: true},
+		},
+		Handler: HandleDefer,
+	})
+	router.Register(&Command{
+		Name: "snooze", Category: "category.tasks",
+		Args: []ArgSpec{
+			{Name: "arg.task_id", Kind: ArgID, Required: true},
+			{Name: "arg.date", Kind: ArgText, Required: true},
+		},
+		Handler: HandleSnooze,
+	})
+	router.Register(&Command{
+		Name: "remind", Category: "category.tasks",
+		Args: []ArgSpec{
+			{Name: "arg.task_id", Kind: ArgID, Required: true},
+			{Name: "arg.time", Kind: ArgText, Required: true},
+		},
+		Handler: HandleRemind,
+	})
+	router.Register(&Command{
+		Name: "focus", Category: "category.tasks",
+		Args: []ArgSpec{
+			{Name: "arg.task_id", Kind: ArgID, Required: true},
+			{Name: "arg.minutes", Kind: ArgInt},
+		},
+		Handler: HandleFocus,
+	})
+	router.Register(&Command{
+		Name: "focus stop", Category: "category.tasks",
+		Handler: HandleFocusStop,
+	})
+	router.Register(&Command{
+		Name: "break", Category: "category.tasks",
+		Args:    []ArgSpec{{Name: "arg.minutes", Kind: ArgInt}},
+		Handler: HandleBreak,
+	})
+	router.Register(&Command{
+		Name: "track start", Category: "category.tasks",
+		Args:    []ArgSpec{{Name: "arg.task_id", Kind: ArgID, Required: true}},
+		Handler: HandleTrackStart,
+	})
+	router.Register(&Command{
+		Name: "track stop", Category: "category.tasks",
+		Handler: HandleTrackStop,
+	})
+	router.Register(&Command{
+		Name: "track add", Category: "category.tasks",
+		Args: []ArgSpec{
+			{Name: "arg.task_id", Kind: ArgID, Required: true},
+			{Name: "arg.duration", Kind: ArgText, Required: true},
+		},
+		Handler: HandleTrackAdd,
+	})
+	router.Register(&Command{
+		Name: "timesheet", Category: "category.tasks",
+		Args:    []ArgSpec{{Name: "arg.period", Kind: ArgText}},
+		Handler: HandleTimesheet,
+	})
+	router.Register(&Command{
+		Name: "estimates", Category: "category.tasks",
+		Handler: HandleEstimates,
+	})
+	router.Register(&Command{
+		Name: "habit", Category: "category.habits",
+		Args:    []ArgSpec{{Name: "arg.habit", Kind: ArgText}},
+		Handler: HandleHabits,
+	})
+	router.Register(&Command{
+		Name: "habit add", Category: "category.habits",
+		Args:    []ArgSpec{{Name: "arg.habit", Kind: ArgText, Required: true}},
+		Handler: HandleHabitAdd,
+	})
+	router.Register(&Command{
+		Name: "habit check", Category: "category.habits",
+		Args:    []ArgSpec{{Name: "arg.habit", Kind: ArgText, Required: true}},
+		Handler: HandleHabitCheck,
+	})
+	router.Register(&Command{
+		Name: "habit delete", Category: "category.habits",
+		Args:    []ArgSpec{{Name: "arg.habit", Kind: ArgText, Required: true}},
+		Handler: HandleHabitDelete,
+	})
+	router.Register(&Command{
+		Name: "edit", Category: "category.tasks",
+		Scoped: true,
+		Args: []ArgSpec{
+			{Name: "arg.number", Kind: ArgInt, Required: true},
+			{Name: "arg.content", Kind: ArgText, Required: true},
+		},
+		Handler: HandleEdit,
+	})
+	router.Register(&Command{
+		Name: "delete", Category: "category.tasks",
+		Scoped:  true,
+		Args:    []ArgSpec{{Name: "arg.number", Kind: ArgInt, Required: true}},
+		Handler: HandleDelete,
+	})
+	router.Register(&Command{
+		Name: "reset", Category: "category.reset",
+		Scoped: true, SharedPermission: PermManager,
+		Handler: HandleReset,
+	})
+	router.Register(&Command{
+		Name: "reset all", Category: "category.reset",
+		Handler: HandleResetAll,
+	})
+	router.Register(&Command{
+		Name: "confirm reset", Category: "category.reset",
+		Handler: HandleConfirm,
+	})
+	router.Register(&Command{
+		Name: "team on", Category: "category.team",
+		Permission: PermManager,
+		Handler:    HandleTeamOn,
+	})
+	router.Register(&Command{
+		Name: "team off", Category: "category.team",
+		Permission: PermManager,
+		Handler:    HandleTeamOff,
+	})
+	router.Register(&Command{
+		Name: "chat", Category: "category.ai",
+		Args:    []ArgSpec{{Name: "arg.message", Kind: ArgText, Required: true}},
+		Handler: HandleChat,
+	})
+	router.Register(&Command{
+		Name: "broadcast", Category: "category.admin",
+		Permission: PermBotAdmin,
+		Args:       []ArgSpec{{Name: "arg.message", Kind: ArgText, Required: true}},
+		Handler:    HandleBroadcast,
+	})
+	router.Register(&Command{
+		Name: "stats", Category: "category.admin",
+		Permission: PermBotAdmin,
+		Handler:    HandleStats,
+	})
+	router.Register(&Command{
+		Name: "digest join", Category: "category.team",
+		Handler: HandleDigestJoin,
+	})
+	router.Register(&Command{
+		Name: "digest leave", Category: "category.team",
+		Handler: HandleDigestLeave,
+	})
+	registerConfigCommands()
+	router.Register(&Command{
+		Name: "help", Aliases: []string{"h"}, Category: "category.help",
+		Handler: HandleHelp,
+	})
 }
 
 func MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
@@ -39,107 +292,187 @@ func MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 	}
 
 	content := strings.TrimSpace(m.ContentWithMentionsReplaced())
-
-	switch {
-	case strings.HasPrefix(content, "!add "):
-		HandleAdd(s, m, content)
-	case strings.HasPrefix(content, "!list"):
-		HandleList(s, m)
-	case strings.HasPrefix(content, "!done "):
-		HandleComplete(s, m, content)
-	case strings.HasPrefix(content, "!delete"):
-		HandleDelete(s, m, content)
-	case strings.HasPrefix(content, "!chat "):
-		HandleChat(s, m, content)
-	case strings.HasPrefix(content, "!reset"):
-		HandleReset(s, m)
-	case strings.HasPrefix(content, "!confirm reset"):
-		HandleConfirm(s, m)
-	case strings.HasPrefix(content, "!edit "):
-		HandleEdit(s, m, content)
-	case strings.HasPrefix(content, "!help"):
-		HandleHelp(s, m)
+	settings, err := service.GetSettings(context.Background(), m.GuildID, m.ChannelID)
+	if err != nil {
+		// 設定が取れなくても既定値で動かす
+		slog.Warn("設定の取得に失敗", "guild_id", m.GuildID, "channel_id", m.ChannelID, "error", err)
 	}
+	router.Dispatch(s, m, content, settings)
 }
 
-func HandleAdd(s *discordgo.Session, m *discordgo.MessageCreate, content string) {
-	args := strings.Fields(strings.TrimPrefix(content, "!add"))
-	if len(args) == 0 {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```⚠️ タスク内容を追加してください```")
+// InteractionCreate はボタンなどのインタラクションを処理します。
+// メッセージにはロケールが含まれないため、ここでユーザのDiscordの言語を記録して以降の返信の既定値にします。
+func InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	user := i.User
+	if i.Member != nil {
+		user = i.Member.User
+	}
+	if user == nil {
 		return
 	}
-	// 優先度を表す部分だけTrim
-	priorityID := 4 // default
-	priorityInput := strings.ToUpper(args[len(args)-1])
-	if pid, ok := priorityMap[priorityInput]; ok {
-		priorityID = pid
-		args = args[:len(args)-1]
+	if err := service.RecordDiscordLocale(context.Background(), user.ID, string(i.Locale)); err != nil {
+		slog.Warn("Discordの言語の保存に失敗", "user_id", user.ID, "error", err)
 	}
-	title := strings.Join(args, " ")
-	err := service.AddTaskService(m.Author.ID, title, priorityID)
-	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```❌ タスク登録失敗```")
+	if i.Type != discordgo.InteractionMessageComponent {
 		return
 	}
-	replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```⭕️ タスク追加: %s 優先度： %d (%s)```", title, priorityID, priorityEmoji[priorityID]))
+	customID := i.MessageComponentData().CustomID
+	switch {
+	case strings.HasPrefix(customID, listButtonPrefix):
+		handleListButton(s, i, user.ID, customID)
+	}
 }
 
-func HandleList(s *discordgo.Session, m *discordgo.MessageCreate) {
-	tasks, err := service.GetTaskService(m.Author.ID)
-	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```❌ タスク取得失敗```")
-		return
+// extractAssignee は引数からメンションされた担当者を取り除き、担当者を返します。
+// メンションは ContentWithMentionsReplaced で "@ユーザ名" に置き換わっています。
+// 返信すると返信先の投稿者も Mentions に入るため、本文に <@ID> が書かれたユーザだけを担当者にします。
+func extractAssignee(ctx *Context) ([]string, *discordgo.User, bool) {
+	var assignee *discordgo.User
+	for _, u := range ctx.Message.Mentions {
+		if u.ID == ctx.Session.State.User.ID || !mentionedInContent(ctx.Message.Content, u.ID) {
+			continue
+		}
+		if assignee != nil && assignee.ID != u.ID {
+			ctx.Reply(ctx.T("add.one_assignee"))
+			return nil, nil, false
+		}
+		assignee = u
 	}
-	if len(tasks) == 0 {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```📭 タスクが登録されていません```")
-		return
+	if assignee == nil {
+		return ctx.Args, nil, true
 	}
-	var msg strings.Builder
-	msg.WriteString("今日のTodoです！\n```")
-	completedFlag := false
-	for i, task := range tasks {
-		if task.Status == "pending" {
-			if i == 0 {
-				msg.WriteString(fmt.Sprintf("📝 未完了のタスク\n"))
+	args := make([]string, 0, len(ctx.Args))
+	for _, arg := range ctx.Args {
+		if arg == "@"+assignee.Username {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args, assignee, true
+}
+
+// mentionedInContent は本文に userID へのメンション（<@ID> または <@!ID>）が書かれているかどうかを返します。
+func mentionedInContent(content, userID string) bool {
+	return strings.Contains(content, "<@"+userID+">") || strings.Contains(content, "<@!"+userID+">")
+}
+
+// displayName はユーザの表示名を返します。取得できなければユーザIDを返します。
+func displayName(s *discordgo.Session, guildID, userID string) string {
+	if guildID != "" {
+		if member, err := s.State.Member(guildID, userID); err == nil {
+			if member.Nick != "" {
+				return member.Nick
 			}
-			msg.WriteString(fmt.Sprintf("%s ⌛️ [%02d] %s\n", priorityEmoji[task.PriorityID], i, task.Title))
-		} else if task.Status == "completed" {
-			if completedFlag == false {
-				msg.WriteString(fmt.Sprintf("\n✅ 完了済みのタスク\n"))
-				completedFlag = true
+			if member.User != nil {
+				return member.User.Username
 			}
-			msg.WriteString(fmt.Sprintf("✅ [%02d] %s\n", i, task.Title))
 		}
 	}
-	msg.WriteString("```")
-	replyToUser(s, m.ChannelID, m.Author.ID, msg.String())
+	if user, err := s.User(userID); err == nil {
+		return user.Username
+	}
+	return userID
 }
 
-func HandleComplete(s *discordgo.Session, m *discordgo.MessageCreate, content string) {
-	arg := strings.TrimPrefix(content, "!done ")
-	DoneTaskNumber, err := strconv.Atoi(arg)
+func HandleAdd(ctx *Context) {
+	args, assignee, ok := extractAssignee(ctx)
+	if !ok {
+		return
+	}
+	// 見積もり（est:2h）はどこに書いてもよい
+	estimate := 0
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		minutes, ok, err := service.ParseEstimate(arg)
+		if err != nil {
+			ctx.ReplyError(err)
+			return
+		}
+		if ok {
+			estimate = minutes
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	args = rest
+	// 優先度を表す部分だけTrim
+	priorityID := 4 // default
+	if len(args) > 0 {
+		if pid, ok := priorityMap[strings.ToUpper(args[len(args)-1])]; ok {
+			priorityID = pid
+			args = args[:len(args)-1]
+		}
+	}
+	if len(args) == 0 {
+		ctx.Reply(ctx.T("add.empty"))
+		return
+	}
+	scope := ctx.Scope
+	title := strings.Join(args, " ")
+	var err error
+	if assignee != nil {
+		err = service.AssignTaskService(ctx.Ctx, scope, ctx.UserID(), assignee.ID, title, priorityID, estimate)
+	} else {
+		err = service.AddTaskService(ctx.Ctx, scope, ctx.UserID(), title, priorityID, estimate)
+	}
 	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```❌ 数字を指定してください```")
+		ctx.Reply(ctx.T("add.failed"))
+		return
+	}
+	if assignee != nil {
+		// 通知は受け取る側の言語で送る
+		lang := service.UserLanguage(ctx.Ctx, assignee.ID, ctx.Settings.Language)
+		notice := i18n.T(lang, "add.assigned_notice", ctx.UserID(), priorityEmoji[priorityID], title)
+		if err := sendDM(ctx.Session, assignee.ID, notice); err != nil {
+			logging.From(ctx.Ctx).Warn("割り当て通知の送信失敗", "assignee_id", assignee.ID, "error", err)
+		}
+		ctx.Reply(ctx.T("add.assigned", assignee.Username, title, priorityID, priorityEmoji[priorityID]) + estimateNote(ctx, estimate))
 		return
 	}
-	err = service.CompleteTaskService(m.Author.ID, DoneTaskNumber)
+	listName := ""
+	if scope.IsShared() {
+		listName = ctx.T("add.shared_suffix")
+	}
+	ctx.Reply(ctx.T("add.success", listName, title, priorityID, priorityEmoji[priorityID]) + estimateNote(ctx, estimate))
+}
+
+// estimateNote は追加したタスクの見積もりの表示です。見積もりがなければ空文字です。
+func estimateNote(ctx *Context, estimate int) string {
+	if estimate == 0 {
+		return ""
+	}
+	return ctx.T("add.estimate", formatMinutes(ctx.Lang, estimate))
+}
+
+func HandleComplete(ctx *Context) {
+	scope := ctx.Scope
+	DoneTaskNumber := ctx.IntArg(0)
+	done, err := service.CompleteTaskService(ctx.Ctx, scope, ctx.UserID(), DoneTaskNumber)
 	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```❌ %s```", err.Error()))
+		ctx.ReplyError(err)
 		return
 	}
-	tasks, err := service.GetTaskService(m.Author.ID)
+	// 割り当てられたタスクなら依頼者に完了を知らせる
+	if done.IsAssigned() && done.CreatedBy != ctx.UserID() {
+		lang := service.UserLanguage(ctx.Ctx, done.CreatedBy, ctx.Settings.Language)
+		notice := i18n.T(lang, "done.notice", ctx.UserID(), done.Title)
+		if err := sendDM(ctx.Session, done.CreatedBy, notice); err != nil {
+			logging.From(ctx.Ctx).Warn("完了通知の送信失敗", "requester_id", done.CreatedBy, "error", err)
+		}
+	}
+	tasks, err := service.GetTaskService(ctx.Ctx, scope)
 	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```✅ タスク完了！\n⚠️ 残りのタスク取得に失敗しました```")
+		ctx.Reply(ctx.T("done.rest_failed"))
 		return
 	}
 	// 内容出力
 	var msg strings.Builder
-	msg.WriteString("```✅ タスク完了！お疲れ様です！\n")
+	msg.WriteString("```" + ctx.T("done.header"))
 	hasPending := false
 	for i, task := range tasks {
-		if task.Status == "pending" {
+		if task.IsOpen() {
 			if !hasPending {
-				msg.WriteString("\n📝 残りのタスク:\n")
+				msg.WriteString(ctx.T("done.remaining"))
 				hasPending = true
 			}
 			msg.WriteString(fmt.Sprintf("⌛️ [%02d] %s\n", i, task.Title))
@@ -148,95 +481,81 @@ func HandleComplete(s *discordgo.Session, m *discordgo.MessageCreate, content st
 	if hasPending {
 		msg.WriteString("```")
 	} else {
-		msg.WriteString("\n🎉 もう残ってるタスクはありません！今日もよく頑張った！```")
+		msg.WriteString(ctx.T("done.all_clear") + "```")
 	}
-	replyToUser(s, m.ChannelID, m.Author.ID, msg.String())
+	ctx.Reply(msg.String())
 }
 
-func HandleDelete(s *discordgo.Session, m *discordgo.MessageCreate, content string) {
-	arg := strings.TrimPrefix(content, "!delete ")
-	DeleteNumber, err := strconv.Atoi(arg)
-	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```❌ 数字を指定してください```")
-		return
-	}
-	err = service.DeleteTaskService(m.Author.ID, DeleteNumber)
+func HandleDelete(ctx *Context) {
+	scope := ctx.Scope
+	DeleteNumber := ctx.IntArg(0)
+	err := service.DeleteTaskService(ctx.Ctx, scope, ctx.actor(), DeleteNumber)
 	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```❌ %s```", err.Error()))
+		ctx.ReplyError(err)
 		return
 	}
-	replyToUser(s, m.ChannelID, m.Author.ID, "```⭕️ タスク削除しました```")
+	ctx.Reply(ctx.T("delete.success"))
 }
 
-func HandleChat(s *discordgo.Session, m *discordgo.MessageCreate, content string) {
-	arg := strings.TrimPrefix(content, "!chat ")
-	if len(strings.TrimSpace(arg)) == 0 {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```❌ メッセージを入力してください```")
+func HandleChat(ctx *Context) {
+	if !ctx.Settings.ChatEnabled {
+		ctx.Reply(ctx.T("chat.disabled"))
 		return
 	}
-	err := s.ChannelTyping(m.ChannelID)
+	arg := ctx.Raw
+	err := ctx.Session.ChannelTyping(ctx.Message.ChannelID)
 	if err != nil {
 		return
 	}
-	reply, err := service.ChatWithContext(m.Author.ID, arg)
+	reply, err := service.ChatWithContext(ctx.Ctx, ctx.UserID(), ctx.Lang, arg)
 	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```❌ %s```", err.Error()))
+		ctx.ReplyError(err)
 		return
 	}
-	replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```\n%s\n```", reply))
+	ctx.ReplyEmbed(ctx.T("chat.title"), reply)
 }
 
-func HandleReset(s *discordgo.Session, m *discordgo.MessageCreate) {
-	if strings.HasPrefix(m.Content, "!reset all") {
-		resetAllConfirm[m.Author.ID] = time.Now().Add(10 * time.Minute)
-		replyToUser(s, m.ChannelID, m.Author.ID,
-			"```⚠️ 本当に全タスク（過去含む）を削除しますか？\n削除するには '!confirm reset' と入力してください。（10分以内）```")
-		return
-	}
-	count, err := service.ResetTodayTasks(m.Author.ID)
+func HandleReset(ctx *Context) {
+	count, err := service.ResetTodayTasks(ctx.Ctx, ctx.Scope)
 	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```❌ 今日のリセット失敗: %s```", err.Error()))
+		ctx.Reply(ctx.T("reset.failed", i18n.Message(ctx.Lang, err)))
 		return
 	}
-	replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```✅ 今日のタスクを %d 件削除しました```", count))
+	ctx.Reply(ctx.T("reset.success", count))
 }
 
-func HandleConfirm(s *discordgo.Session, m *discordgo.MessageCreate) {
-	userID := m.Author.ID
+func HandleResetAll(ctx *Context) {
+	resetAllConfirm[ctx.UserID()] = time.Now().Add(10 * time.Minute)
+	ctx.Reply(ctx.T("reset_all.confirm", ctx.Settings.Prefix))
+}
+
+func HandleConfirm(ctx *Context) {
+	userID := ctx.UserID()
 	expiry, ok := resetAllConfirm[userID]
 	if !ok || time.Now().After(expiry) {
 		delete(resetAllConfirm, userID)
-		replyToUser(s, m.ChannelID, userID, "```⚠️ '!reset all' の確認時間が切れました。再度実行してください。```")
+		ctx.Reply(ctx.T("reset_all.expired", ctx.Settings.Prefix))
 		return
 	}
 
-	count, err := service.ResetAllTasks(userID)
+	count, err := service.ResetAllTasks(ctx.Ctx, userID)
 	if err != nil {
-		replyToUser(s, m.ChannelID, userID, fmt.Sprintf("```❌ 全削除に失敗しました: %s```", err.Error()))
+		ctx.Reply(ctx.T("reset_all.failed", i18n.Message(ctx.Lang, err)))
 		return
 	}
 	delete(resetAllConfirm, userID)
-	replyToUser(s, m.ChannelID, userID, fmt.Sprintf("```✅ 全タスクを %d 件削除しました```", count))
+	ctx.Reply(ctx.T("reset_all.success", count))
 }
 
-func HandleEdit(s *discordgo.Session, m *discordgo.MessageCreate, content string) {
-	arg := strings.TrimPrefix(content, "!edit ")
-	fields := strings.Fields(arg)
-	if len(fields) < 2 {
-		replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```⚠️ コマンドの形式が正しくありません。\n例: `!edit 1 <title/優先度>` or `!edit 1 title 優先度` ```"))
-		return
-	}
-	IndexNumber, err := strconv.Atoi(fields[0])
-	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, "```❌ 数字を指定してください```")
-		return
-	}
+func HandleEdit(ctx *Context) {
+	scope := ctx.Scope
+	IndexNumber := ctx.IntArg(0)
 	// validate input
-	params := fields[1:]
+	params := ctx.Args[1:]
 	var newPriority *int
 	var newTitle string
 
-	if pid, ok := priorityMap[params[len(params)-1]]; ok {
+	if pid, ok := priorityMap[strings.ToUpper(params[len(params)-1])]; ok {
 		// paramの末尾が優先度指定なら設定
 		newPriority = &pid
 	}
@@ -252,32 +571,79 @@ func HandleEdit(s *discordgo.Session, m *discordgo.MessageCreate, content string
 		newTitle = strings.Join(params[0:titleEnd], " ")
 	}
 
-	err = service.UpdateTaskService(m.Author.ID, IndexNumber, newTitle, newPriority)
+	err := service.UpdateTaskService(ctx.Ctx, scope, ctx.actor(), IndexNumber, newTitle, newPriority)
+	if err != nil {
+		ctx.Reply(ctx.T("edit.failed", i18n.Message(ctx.Lang, err)))
+		return
+	}
+	ctx.Reply(ctx.T("edit.success"))
+}
+
+// HandleDigestJoin はサーバーのデイリーダイジェストに参加します。
+func HandleDigestJoin(ctx *Context) {
+	if err := service.JoinDigest(ctx.Ctx, ctx.Message.GuildID, ctx.UserID()); err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	msg := ctx.T("digest.joined")
+	if ctx.Settings.DigestChannelID == "" {
+		msg += ctx.T("digest.no_channel", ctx.Settings.Prefix)
+	}
+	ctx.Reply(msg)
+}
+
+// HandleDigestLeave はサーバーのデイリーダイジェストから抜けます。
+func HandleDigestLeave(ctx *Context) {
+	if err := service.LeaveDigest(ctx.Ctx, ctx.Message.GuildID, ctx.UserID()); err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("digest.left"))
+}
+
+// HandleAssigned は自分が他のユーザに割り当てたタスクを表示します。
+func HandleAssigned(ctx *Context) {
+	tasks, err := service.GetAssignedTaskService(ctx.Ctx, ctx.UserID())
+	if err != nil {
+		ctx.Reply(ctx.T("common.fetch_failed"))
+		return
+	}
+	if len(tasks) == 0 {
+		ctx.Reply(ctx.T("assigned.empty"))
+		return
+	}
+	var msg strings.Builder
+	msg.WriteString(ctx.T("assigned.header") + "```")
+	for _, task := range tasks {
+		msg.WriteString(fmt.Sprintf("%s %s %s → %s\n", priorityEmoji[task.PriorityID], statusEmoji[task.Status], task.Title,
+			displayName(ctx.Session, ctx.Message.GuildID, task.AssigneeID)))
+	}
+	msg.WriteString("```")
+	ctx.Reply(msg.String())
+}
+
+// HandleTeamOn はチャンネルを共有リストモードにします。
+func HandleTeamOn(ctx *Context) {
+	err := service.SetChannelListMode(ctx.Ctx, ctx.Message.GuildID, ctx.Message.ChannelID, ctx.UserID(), true)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("team.on", ctx.Settings.Prefix))
+}
+
+// HandleTeamOff はチャンネルの共有リストモードを解除します。
+func HandleTeamOff(ctx *Context) {
+	err := service.SetChannelListMode(ctx.Ctx, ctx.Message.GuildID, ctx.Message.ChannelID, ctx.UserID(), false)
 	if err != nil {
-		replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```❌ タスクの編集に失敗しました: %s```", err.Error()))
+		ctx.ReplyError(err)
 		return
 	}
-	replyToUser(s, m.ChannelID, m.Author.ID, fmt.Sprintf("```✅ 指定されたToDoを編集しました```"))
+	ctx.Reply(ctx.T("team.off", ctx.Settings.Prefix))
 }
 
-func HandleHelp(s *discordgo.Session, m *discordgo.MessageCreate) {
-	helpText := "**📋 Self-Management Bot コマンド一覧**\n" +
-		"以下のコマンドを使って、タスクの管理やAIとの対話ができます！\n\n" +
-		"```" +
-		"✅ タスク管理\n" +
-		"!add <タスク名> [P1~P4]        : タスクを追加（例: !add 宿題 P1）\n" +
-		"!list                         : 今日のタスクを一覧表示\n" +
-		"!done <番号>                  : 指定タスクを完了扱いに\n" +
-		"!edit <番号> <内容> [P1~P4]   : 内容や優先度を編集\n" +
-		"!delete <番号>                : 指定タスクを削除\n\n" +
-		"♻️ タスク全削除（慎重に）\n" +
-		"!reset                        : 今日のタスクを全削除\n" +
-		"!reset all                    : 全タスクを削除（確認付き）\n" +
-		"!confirm reset                : 全削除を確定\n\n" +
-		"🤖 AI機能\n" +
-		"!chat <メッセージ>            : AIと会話（モチベ維持や相談）\n\n" +
-		"❓ ヘルプ\n" +
-		"!help                         : このヘルプを再表示\n" +
-		"```"
-	replyToUser(s, m.ChannelID, m.Author.ID, helpText)
+// HandleHelp は登録済みコマンドからヘルプを生成して表示します。
+func HandleHelp(ctx *Context) {
+	helpText := ctx.T("help.title") + "```" + router.HelpText(ctx.Settings.Prefix, ctx.Lang) + "```"
+	ctx.Reply(helpText)
 }
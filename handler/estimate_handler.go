@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"self-management-bot/service"
+	"strings"
+	"time"
+)
+
+// HandleEstimates は最近完了したタスクの見積もりと実績を優先度ごとに比べます。
+func HandleEstimates(ctx *Context) {
+	report, err := service.EstimateReportService(ctx.Ctx, ctx.UserID(), time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	var msg strings.Builder
+	msg.WriteString(ctx.T("estimates.header", report.Since))
+	if report.Total.Count == 0 {
+		msg.WriteString(ctx.T("estimates.empty"))
+	}
+	for _, row := range report.Rows {
+		label := ctx.T("estimates.priority", priorityEmoji[row.PriorityID], row.PriorityID)
+		msg.WriteString(formatEstimateRow(ctx, label, row))
+	}
+	if len(report.Rows) > 1 {
+		msg.WriteString(formatEstimateRow(ctx, ctx.T("estimates.total"), report.Total))
+	}
+	if report.Unknown > 0 {
+		msg.WriteString(ctx.T("estimates.unknown", report.Unknown))
+	}
+	ctx.Reply(msg.String())
+}
+
+// formatEstimateRow は見積もりと実績の1行です。実績が見積もりの何％だったかを付けます。
+func formatEstimateRow(ctx *Context, label string, row service.EstimateRow) string {
+	ratio := 0
+	if row.Estimated > 0 {
+		ratio = row.Actual * 100 / row.Estimated
+	}
+	return ctx.T("estimates.row", label, row.Count,
+		formatMinutes(ctx.Lang, row.Estimated), formatMinutes(ctx.Lang, row.Actual), ratio)
+}
+
+// HandleHours は1日に使える時間を表示・変更します（例: !hours 6、!hours reset）。
+// 毎朝のリマインドで、今日の見積もりの合計がこの時間を超えていれば知らせます。
+func HandleHours(ctx *Context) {
+	if len(ctx.Args) == 0 {
+		ctx.Reply(ctx.T("hours.current", formatMinutes(ctx.Lang, service.AvailableMinutes(ctx.Ctx, ctx.UserID()))))
+		return
+	}
+	value := strings.ToLower(ctx.Args[0])
+	minutes, err := service.SetAvailableHours(ctx.Ctx, ctx.UserID(), value)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("hours.updated", formatMinutes(ctx.Lang, minutes)))
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"self-management-bot/service"
+	"time"
+)
+
+// HandleFocus はタスクに集中するタイマーを始めます（例: !focus 12 25）。終わったらDMで知らせます。
+func HandleFocus(ctx *Context) {
+	minutes := service.DefaultFocusMinutes
+	if len(ctx.Args) > 1 {
+		minutes = ctx.IntArg(1)
+	}
+	started, err := service.StartFocusService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0), minutes, time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("focus.started", started.Task.ID, started.Task.Title, minutes, started.EndsAt.Format("15:04")))
+}
+
+// HandleBreak は休憩タイマーを始めます（例: !break 5）。
+func HandleBreak(ctx *Context) {
+	minutes := service.DefaultBreakMinutes
+	if len(ctx.Args) > 0 {
+		minutes = ctx.IntArg(0)
+	}
+	started, err := service.StartBreakService(ctx.Ctx, ctx.UserID(), minutes, time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("break.started", minutes, started.EndsAt.Format("15:04")))
+}
+
+// HandleFocusStop は実行中の集中・休憩タイマーを止めます。
+func HandleFocusStop(ctx *Context) {
+	session, err := service.StopFocusService(ctx.Ctx, ctx.UserID(), time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	if session.Kind == repository.FocusKindBreak {
+		ctx.Reply(ctx.T("break.stopped"))
+		return
+	}
+	ctx.Reply(ctx.T("focus.stopped", session.TaskID.Int64, session.Title, formatMinutes(ctx.Lang, session.Minutes)))
+}
+
+// formatMinutes は分数を "1時間15分" のように表示します。
+func formatMinutes(lang string, minutes int) string {
+	if minutes >= 60 {
+		return i18n.T(lang, "duration.hours_minutes", minutes/60, minutes%60)
+	}
+	return i18n.T(lang, "duration.minutes", minutes)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"self-management-bot/i18n"
+	"self-management-bot/service"
+	"strings"
+	"time"
+)
+
+// heatmapWeeks はヒートマップに表示する週の数です。
+const heatmapWeeks = 12
+
+// HandleHabits は習慣の一覧と連続日数を表示します。名前を指定するとその習慣のカレンダー（ヒートマップ）を表示します。
+func HandleHabits(ctx *Context) {
+	now := time.Now()
+	if name := strings.TrimSpace(ctx.Raw); name != "" {
+		status, err := service.HabitStatusService(ctx.Ctx, ctx.UserID(), name, now)
+		if err != nil {
+			ctx.ReplyError(err)
+			return
+		}
+		ctx.Reply(formatHabitHeatmap(ctx.Lang, status, now))
+		return
+	}
+	statuses, err := service.HabitStatusesService(ctx.Ctx, ctx.UserID(), now)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	if len(statuses) == 0 {
+		ctx.Reply(ctx.T("habit.empty"))
+		return
+	}
+	var msg strings.Builder
+	msg.WriteString(ctx.T("habit.header"))
+	for _, s := range statuses {
+		msg.WriteString(service.HabitLine(ctx.Lang, s))
+	}
+	ctx.Reply(msg.String())
+}
+
+// HandleHabitAdd は習慣を追加します（例: !habit add 筋トレ）。
+func HandleHabitAdd(ctx *Context) {
+	name := strings.TrimSpace(ctx.Raw)
+	if err := service.AddHabitService(ctx.Ctx, ctx.UserID(), name); err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("habit.added", name))
+}
+
+// HandleHabitCheck は習慣を今日やったと記録します（例: !habit check 筋トレ）。
+func HandleHabitCheck(ctx *Context) {
+	status, err := service.CheckHabitService(ctx.Ctx, ctx.UserID(), ctx.Raw, time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	msg := ctx.T("habit.checked", status.Name, status.Current)
+	if status.Current > 1 && status.Current == status.Longest {
+		msg += ctx.T("habit.record")
+	}
+	ctx.Reply(msg)
+}
+
+// HandleHabitDelete は習慣をチェックの記録ごと削除します。
+func HandleHabitDelete(ctx *Context) {
+	habit, err := service.DeleteHabitService(ctx.Ctx, ctx.UserID(), ctx.Raw)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("habit.deleted", habit.Name))
+}
+
+// formatHabitHeatmap は直近 heatmapWeeks 週のチェックを、曜日を行・週を列にしたカレンダーで表示します。
+func formatHabitHeatmap(lang string, status service.HabitStatus, now time.Time) string {
+	var msg strings.Builder
+	msg.WriteString(i18n.T(lang, "habit.heatmap_header", status.Name, heatmapWeeks))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// 月曜始まり。今週の月曜から heatmapWeeks-1 週さかのぼる
+	start := today.AddDate(0, 0, -(int(today.Weekday())+6)%7-7*(heatmapWeeks-1))
+	weekdays := strings.Split(i18n.T(lang, "habit.weekdays"), ",")
+	for row := 0; row < 7; row++ {
+		msg.WriteString(weekdays[row] + " ")
+		for week := 0; week < heatmapWeeks; week++ {
+			day := start.AddDate(0, 0, 7*week+row)
+			switch {
+			case day.After(today):
+				// 今週のこれからの日は表示しない
+			case status.CheckedOn(day.Format(time.DateOnly)):
+				msg.WriteString("🟩")
+			default:
+				msg.WriteString("⬜")
+			}
+		}
+		msg.WriteString("\n")
+	}
+	msg.WriteString(service.HabitLine(lang, status))
+	return msg.String()
+}
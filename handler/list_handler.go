@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+	"self-management-bot/service"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// listSessionTTL はページ送りボタンが使える時間です。
+const listSessionTTL = 30 * time.Minute
+
+// listButtonPrefix はページ送りボタンの CustomID の接頭辞です（"list:<セッションID>:<ページ>"）。
+const listButtonPrefix = "list:"
+
+// listStatusWords は !list で状態を指定する語です。
+var listStatusWords = map[string]string{
+	"open":        repository.StatusFilterOpen,
+	"pending":     repository.StatusFilterOpen,
+	"未完了":         repository.StatusFilterOpen,
+	"todo":        repository.StatusTodo,
+	"未着手":         repository.StatusTodo,
+	"doing":       repository.StatusInProgress,
+	"in_progress": repository.StatusInProgress,
+	"進行中":         repository.StatusInProgress,
+	"blocked":     repository.StatusBlocked,
+	"ブロック":        repository.StatusBlocked,
+	"waiting":     repository.StatusWaiting,
+	"待ち":          repository.StatusWaiting,
+	"done":        repository.StatusDone,
+	"completed":   repository.StatusDone,
+	"完了":          repository.StatusDone,
+	"cancelled":   repository.StatusCancelled,
+	"canceled":    repository.StatusCancelled,
+	"キャンセル":       repository.StatusCancelled,
+	"snoozed":     repository.StatusFilterSnoozed,
+	"スヌーズ":        repository.StatusFilterSnoozed,
+	"all":         repository.StatusFilterAll,
+	"全部":          repository.StatusFilterAll,
+}
+
+// listSession はページ送りに必要な、最初の !list の条件です。
+type listSession struct {
+	Filter  repository.TaskFilter
+	UserID  string
+	GuildID string
+	Lang    string
+	Expires time.Time
+}
+
+var (
+	listSessionsMu sync.Mutex
+	listSessions   = map[string]listSession{}
+)
+
+// saveListSession はセッションを保存してIDを返します。期限切れのセッションはここで掃除します。
+func saveListSession(session listSession) string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	id := hex.EncodeToString(b)
+
+	listSessionsMu.Lock()
+	defer listSessionsMu.Unlock()
+	now := time.Now()
+	for k, v := range listSessions {
+		if now.After(v.Expires) {
+			delete(listSessions, k)
+		}
+	}
+	listSessions[id] = session
+	return id
+}
+
+func loadListSession(id string) (listSession, bool) {
+	listSessionsMu.Lock()
+	defer listSessionsMu.Unlock()
+	session, ok := listSessions[id]
+	if !ok || time.Now().After(session.Expires) {
+		return listSession{}, false
+	}
+	return session, true
+}
+
+// parseListFilter は !list の引数を絞り込み条件にします。
+// P1~P4 / pending・done・all / since:YYYY-MM-DD / until:YYYY-MM-DD 以外の語はタイトルの検索語として扱います。
+func parseListFilter(scope repository.Scope, args []string) (repository.TaskFilter, error) {
+	filter := repository.TaskFilter{Scope: scope}
+	statusGiven := false
+	var words []string
+	for _, arg := range args {
+		lower := strings.ToLower(arg)
+		if pid, ok := priorityMap[strings.ToUpper(arg)]; ok {
+			filter.PriorityID = pid
+			continue
+		}
+		if status, ok := listStatusWords[lower]; ok {
+			filter.Status = status
+			statusGiven = true
+			continue
+		}
+		if key, value, ok := strings.Cut(lower, ":"); ok && (key == "since" || key == "until") {
+			if _, err := time.Parse(time.DateOnly, value); err != nil {
+				return filter, i18n.NewError("err.invalid_date", arg)
+			}
+			if key == "since" {
+				filter.Since = value
+			} else {
+				filter.Until = value
+			}
+			continue
+		}
+		words = append(words, arg)
+	}
+	filter.Text = strings.Join(words, " ")
+	// 期間を指定したときは、今日の分に限らず期間内のすべてを対象にする
+	if !statusGiven && (filter.Since != "" || filter.Until != "") {
+		filter.Status = repository.StatusFilterAll
+	}
+	return filter, nil
+}
+
+func HandleList(ctx *Context) {
+	filter, err := parseListFilter(ctx.Scope, ctx.Args)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	page, err := service.ListTasksService(ctx.Ctx, filter, 0)
+	if err != nil {
+		ctx.Reply(ctx.T("common.fetch_failed"))
+		return
+	}
+	if page.Total == 0 {
+		if filter != (repository.TaskFilter{Scope: ctx.Scope}) {
+			ctx.Reply(ctx.T("list.no_match"))
+			return
+		}
+		ctx.Reply(ctx.T("list.empty"))
+		return
+	}
+	session := listSession{
+		Filter:  filter,
+		UserID:  ctx.UserID(),
+		GuildID: ctx.Message.GuildID,
+		Lang:    ctx.Lang,
+		Expires: time.Now().Add(listSessionTTL),
+	}
+	content := renderTaskPage(ctx.Session, session, page)
+	if page.TotalPages == 1 {
+		ctx.Reply(content)
+		return
+	}
+	ctx.ReplyWithComponents(content, listButtons(saveListSession(session), page))
+}
+
+// renderTaskPage はタスク一覧の1ページを表示用の文字列にします。
+func renderTaskPage(s *discordgo.Session, session listSession, page service.TaskPage) string {
+	lang, scope := session.Lang, session.Filter.Scope
+	// 共有リストでは誰が追加・完了したかを併記する
+	names := map[string]string{}
+	who := func(userID string) string {
+		if _, ok := names[userID]; !ok {
+			names[userID] = displayName(s, session.GuildID, userID)
+		}
+		return names[userID]
+	}
+	number := func(task repository.Task) string {
+		if n, ok := page.Numbers[task.ID]; ok {
+			return fmt.Sprintf("[%02d] #%d", n, task.ID)
+		}
+		return fmt.Sprintf("[--] #%d", task.ID)
+	}
+	var msg strings.Builder
+	if scope.IsShared() {
+		msg.WriteString(i18n.T(lang, "list.shared_header", scope.ChannelID) + "```")
+	} else if session.Filter != (repository.TaskFilter{Scope: scope}) {
+		msg.WriteString(i18n.T(lang, "list.filtered_header") + "```")
+	} else {
+		msg.WriteString(i18n.T(lang, "list.header") + "```")
+	}
+	// タスクは Statuses の順に並んで届くので、状態が変わるところに見出しを入れる
+	group := ""
+	for _, task := range page.Tasks {
+		if task.Status != group {
+			if group != "" {
+				msg.WriteString("\n")
+			}
+			msg.WriteString(i18n.T(lang, "list.group."+task.Status))
+			group = task.Status
+		}
+		if task.IsOpen() {
+			msg.WriteString(fmt.Sprintf("%s %s %s %s", priorityEmoji[task.PriorityID], statusEmoji[task.Status], number(task), task.Title))
+			if scope.IsShared() {
+				msg.WriteString(fmt.Sprintf(" (👤 %s)", who(task.CreatedBy)))
+				if task.IsAssigned() {
+					msg.WriteString(fmt.Sprintf(" → %s", who(task.AssigneeID)))
+				}
+			} else if task.IsAssigned() {
+				msg.WriteString(fmt.Sprintf(" (📨 %s)", who(task.CreatedBy)))
+			}
+			if task.CarryOverDays > 0 {
+				msg.WriteString(i18n.T(lang, "list.age", task.CarryOverDays+1))
+			}
+		} else {
+			msg.WriteString(fmt.Sprintf("%s %s %s", statusEmoji[task.Status], number(task), task.Title))
+			if scope.IsShared() {
+				msg.WriteString(fmt.Sprintf(" (👤 %s / %s %s)", who(task.CreatedBy), statusEmoji[task.Status], who(task.CompletedBy)))
+			}
+		}
+		if task.EstimateMinutes > 0 {
+			msg.WriteString(" 📐" + formatMinutes(lang, task.EstimateMinutes))
+		}
+		if minutes := page.FocusMinutes[task.ID]; minutes > 0 {
+			msg.WriteString(" 🍅" + formatMinutes(lang, minutes))
+		}
+		if task.StatusReason != "" {
+			msg.WriteString(fmt.Sprintf(" — %s", task.StatusReason))
+		}
+		msg.WriteString("\n")
+	}
+	if page.FocusToday > 0 {
+		msg.WriteString(i18n.T(lang, "list.focus_today", formatMinutes(lang, page.FocusToday)))
+	}
+	if page.TotalPages > 1 {
+		msg.WriteString(i18n.T(lang, "list.page", page.Page+1, page.TotalPages, page.Total))
+	}
+	msg.WriteString("```")
+	return msg.String()
+}
+
+// listButtons は前後のページへのボタンです。端のページでは押せなくします。
+func listButtons(sessionID string, page service.TaskPage) []discordgo.MessageComponent {
+	customID := func(p int) string {
+		return fmt.Sprintf("%s%s:%d", listButtonPrefix, sessionID, p)
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Emoji:    discordgo.ComponentEmoji{Name: "◀️"},
+				Style:    discordgo.SecondaryButton,
+				CustomID: customID(page.Page - 1),
+				Disabled: page.Page == 0,
+			},
+			discordgo.Button{
+				Emoji:    discordgo.ComponentEmoji{Name: "▶️"},
+				Style:    discordgo.SecondaryButton,
+				CustomID: customID(page.Page + 1),
+				Disabled: page.Page >= page.TotalPages-1,
+			},
+		}},
+	}
+}
+
+// handleListButton はページ送りボタンが押されたときに、メッセージをそのページに書き換えます。
+func handleListButton(s *discordgo.Session, i *discordgo.InteractionCreate, userID, customID string) {
+	ctx := logging.NewContext(context.Background(), logging.Fields{
+		UserID:    userID,
+		GuildID:   i.GuildID,
+		ChannelID: i.ChannelID,
+		Command:   "list",
+	})
+	logger := logging.From(ctx)
+	sessionID, pageText, _ := strings.Cut(strings.TrimPrefix(customID, listButtonPrefix), ":")
+	pageNumber, err := strconv.Atoi(pageText)
+	if err != nil {
+		logger.Warn("不正なボタン", "custom_id", customID)
+		return
+	}
+	session, ok := loadListSession(sessionID)
+	if !ok {
+		respondEphemeral(ctx, s, i, i18n.T(service.UserLanguage(ctx, userID, i18n.Default), "list.expired"))
+		return
+	}
+	if session.UserID != userID {
+		respondEphemeral(ctx, s, i, i18n.T(service.UserLanguage(ctx, userID, session.Lang), "list.not_owner"))
+		return
+	}
+	page, err := service.ListTasksService(ctx, session.Filter, pageNumber)
+	if err != nil {
+		logger.Error("タスク取得失敗", "error", err)
+		respondEphemeral(ctx, s, i, i18n.T(session.Lang, "common.fetch_failed"))
+		return
+	}
+	content := renderTaskPage(s, session, page)
+	if i.GuildID != "" {
+		content = fmt.Sprintf("<@%s>\n", userID) + content
+	}
+	// 1メッセージに収まらない場合は、コードブロックを閉じた先頭部分だけを表示する
+	if chunks := splitMessage(content, messageLimit); len(chunks) > 1 {
+		content = chunks[0]
+	}
+	components := listButtons(sessionID, page)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: components,
+		},
+	})
+	if err != nil {
+		logger.Error("Discord送信エラー", "error", err)
+	}
+}
+
+// respondEphemeral はボタンを押したユーザにだけ見えるメッセージで応答します。
+func respondEphemeral(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logging.From(ctx).Error("Discord送信エラー", "error", err)
+	}
+}
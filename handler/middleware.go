@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"expvar"
+	"runtime/debug"
+	"self-management-bot/logging"
+	"sync"
+	"time"
+)
+
+// Recover はハンドラ内の panic を捕まえ、Bot全体が落ちないようにします。
+func Recover(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.From(ctx.Ctx).Error("panic in command", "panic", r, "stack", string(debug.Stack()))
+				ctx.Reply(ctx.T("common.internal_error"))
+			}
+		}()
+		next(ctx)
+	}
+}
+
+// Logging はコマンドの実行と所要時間を記録します。
+func Logging(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		logger := logging.From(ctx.Ctx)
+		logger.Info("command received", "invoked", ctx.Invoked)
+		start := time.Now()
+		next(ctx)
+		logger.Info("command finished", "duration", time.Since(start))
+	}
+}
+
+// RateLimit はユーザごとに window 内で limit 回までコマンド実行を許可します。
+// 履歴が増え続けないよう、window ごとに window 内に実行していないユーザの履歴を消します。
+func RateLimit(limit int, window time.Duration) Middleware {
+	var mu sync.Mutex
+	history := map[string][]time.Time{}
+	var lastPrune time.Time
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			now := time.Now()
+			userID := ctx.UserID()
+
+			mu.Lock()
+			if now.Sub(lastPrune) >= window {
+				pruneHistory(history, now, window)
+				lastPrune = now
+			}
+			recent := history[userID][:0]
+			for _, t := range history[userID] {
+				if now.Sub(t) < window {
+					recent = append(recent, t)
+				}
+			}
+			allowed := len(recent) < limit
+			if allowed {
+				recent = append(recent, now)
+			}
+			history[userID] = recent
+			mu.Unlock()
+
+			if !allowed {
+				logging.From(ctx.Ctx).Warn("rate limited")
+				ctx.Reply(ctx.T("common.rate_limited"))
+				return
+			}
+			next(ctx)
+		}
+	}
+}
+
+// pruneHistory は最後の実行から window 以上たったユーザの履歴を消します。
+func pruneHistory(history map[string][]time.Time, now time.Time, window time.Duration) {
+	for userID, times := range history {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) >= window {
+			delete(history, userID)
+		}
+	}
+}
+
+// commandCount, commandDuration はコマンドごとの実行回数と累計処理時間(ms)です。
+// METRICS_ADDR を設定すると /debug/vars から参照できます。
+var (
+	commandCount    = expvar.NewMap("command_count")
+	commandDuration = expvar.NewMap("command_duration_ms")
+)
+
+// Metrics はコマンドごとの実行回数と処理時間を集計します。
+func Metrics(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		start := time.Now()
+		next(ctx)
+		commandCount.Add(ctx.Command.Name, 1)
+		commandDuration.Add(ctx.Command.Name, time.Since(start).Milliseconds())
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPruneHistory(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	window := time.Minute
+	history := map[string][]time.Time{
+		"idle":   {now.Add(-3 * time.Minute), now.Add(-2 * time.Minute)},
+		"edge":   {now.Add(-window)},
+		"active": {now.Add(-2 * time.Minute), now.Add(-10 * time.Second)},
+		"empty":  {},
+	}
+	pruneHistory(history, now, window)
+	if len(history) != 1 {
+		t.Fatalf("history = %v, want only active", history)
+	}
+	if got := len(history["active"]); got != 2 {
+		t.Errorf("active history = %d entries, want 2", got)
+	}
+}
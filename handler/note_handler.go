@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"fmt"
+	"self-management-bot/repository"
+	"self-management-bot/service"
+	"strings"
+)
+
+// HandleNote はタスクにメモを追記します。メモは複数行でも構いません。
+func HandleNote(ctx *Context) {
+	taskID := ctx.IntArg(0)
+	task, err := service.AddNoteService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, taskID, ctx.RestAfter(0))
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("note.added", task.ID, task.Title))
+}
+
+// HandleShow はタスクの詳細とメモを表示します。
+func HandleShow(ctx *Context) {
+	detail, err := service.GetTaskDetailService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0))
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	task := detail.Task
+	who := func(userID string) string {
+		return displayName(ctx.Session, ctx.Message.GuildID, userID)
+	}
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("📌 **#%d %s**\n", task.ID, task.Title))
+	msg.WriteString(ctx.T("show.status", statusLabel(ctx, task.Status), priorityEmoji[task.PriorityID], task.PriorityID))
+	if task.Scope == repository.ScopeChannel {
+		msg.WriteString(ctx.T("show.list_shared", task.ScopeID))
+	} else {
+		msg.WriteString(ctx.T("show.list_personal"))
+	}
+	msg.WriteString(ctx.T("show.created", task.CreatedOn, who(task.CreatedBy)))
+	if task.AssigneeID != "" {
+		msg.WriteString(ctx.T("show.assignee", who(task.AssigneeID)))
+	}
+	if task.StatusReason != "" {
+		msg.WriteString(ctx.T("show.reason", task.StatusReason))
+	}
+	if task.SnoozedUntil.Valid {
+		msg.WriteString(ctx.T("show.snoozed", task.SnoozedUntil.String))
+	}
+	if task.EstimateMinutes > 0 {
+		msg.WriteString(ctx.T("show.estimate", formatMinutes(ctx.Lang, task.EstimateMinutes)))
+	}
+	if detail.FocusMinutes > 0 {
+		msg.WriteString(ctx.T("show.focus", formatMinutes(ctx.Lang, detail.FocusMinutes)))
+	}
+	if len(detail.Reminders) > 0 {
+		times := make([]string, len(detail.Reminders))
+		for i, r := range detail.Reminders {
+			times[i] = r.RemindAt
+		}
+		msg.WriteString(ctx.T("show.reminders", strings.Join(times, ", ")))
+	}
+	if task.CompletedOn.Valid && task.Status == repository.StatusCancelled {
+		msg.WriteString(ctx.T("show.cancelled", task.CompletedOn.String, who(task.CompletedBy)))
+	} else if task.CompletedOn.Valid {
+		msg.WriteString(ctx.T("show.completed", task.CompletedOn.String, who(task.CompletedBy)))
+	}
+	if len(detail.Notes) == 0 {
+		msg.WriteString(ctx.T("show.no_notes", ctx.Settings.Prefix, task.ID))
+	} else {
+		msg.WriteString(ctx.T("show.notes"))
+		for _, n := range detail.Notes {
+			msg.WriteString(fmt.Sprintf("`%s` %s\n%s\n", n.CreatedAt, who(n.UserID), n.Body))
+		}
+	}
+	if len(detail.Events) > 0 {
+		msg.WriteString(ctx.T("show.history"))
+		for _, e := range detail.Events {
+			msg.WriteString(fmt.Sprintf("`%s` %s: %s → %s", e.CreatedAt, who(e.UserID), statusLabel(ctx, e.FromStatus), statusLabel(ctx, e.ToStatus)))
+			if e.Reason != "" {
+				msg.WriteString(fmt.Sprintf(" — %s", e.Reason))
+			}
+			msg.WriteString("\n")
+		}
+	}
+	ctx.Reply(msg.String())
+}
+
+// statusLabel はタスクの状態を表示用の文字列にします。
+func statusLabel(ctx *Context, status string) string {
+	return ctx.T("status." + status)
+}
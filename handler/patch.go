@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"github.com/bwmarrin/discordgo"
-	"log"
+	"self-management-bot/logging"
+	"self-management-bot/scheduler"
 	"self-management-bot/service"
 	"time"
 )
 
 // StartResetConfirmCleaner は、リセット確認の有効期限が切れたものを掃除します。
+// リセット確認はプロセスのメモリにあるので、scheduler ではなく各プロセスのTickerで掃除します。
 // 注意: resetAllConfirmへのアクセスはスレッドセーフである必要があります。
 func StartResetConfirmCleaner() {
 	go func() {
@@ -27,54 +31,167 @@ func StartResetConfirmCleaner() {
 	}()
 }
 
-// StartFixedReminderSender は、指定された時刻にリマインダーを送信します。
-// 送信時刻: 6:00, 12:00, 19:00
-func StartFixedReminderSender(s *discordgo.Session) {
-	go func() {
-		// 1分ごとに時刻をチェックするTicker
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
+// RegisterJobs は定期実行するジョブを scheduler に登録します。
+// 時刻はすべてローカル時刻で、停止中に過ぎた回は MaxDelay 以内なら起動後に実行します。
+func RegisterJobs(s *discordgo.Session) {
+	// 定時リマインド（6:00, 12:00, 19:00）。1時間以上遅れたら古い内容なので送らない
+	scheduler.Register(scheduler.Job{
+		Name: "fixed_reminder", Schedule: "0 6,12,19 * * *", MaxDelay: time.Hour,
+		Run: func(ctx context.Context) error { return SendReminder(ctx, s) },
+	})
+	// チームのデイリーダイジェスト（7:00）
+	scheduler.Register(scheduler.Job{
+		Name: "daily_digest", Schedule: "0 7 * * *", MaxDelay: 2 * time.Hour,
+		Run: func(ctx context.Context) error { return SendDailyDigest(ctx, s) },
+	})
+	// 持ち越し日数を数える（0:00）。同じ日に2回は数えないので、どれだけ遅れても実行する
+	scheduler.Register(scheduler.Job{
+		Name: "rollover", Schedule: "0 0 * * *",
+		Run: RunRollover,
+	})
+	// スヌーズが明けたタスクを戻して通知する（6:00）
+	scheduler.Register(scheduler.Job{
+		Name: "snooze_wake", Schedule: "0 6 * * *",
+		Run: func(ctx context.Context) error { return WakeSnoozedTasks(ctx, s) },
+	})
+	// タスクごとのリマインド（!remind）。送信時刻はリマインドごとにDBにあるので、毎分確認する
+	scheduler.Register(scheduler.Job{
+		Name: "task_reminders", Schedule: "* * * * *",
+		Run: func(ctx context.Context) error { return SendDueReminders(ctx, s) },
+	})
+	// 集中・休憩タイマーの終了を知らせる
+	scheduler.Register(scheduler.Job{
+		Name: "focus_timers", Schedule: "* * * * *",
+		Run: func(ctx context.Context) error { return SendFocusEnded(ctx, s) },
+	})
+}
 
-		for t := range ticker.C {
-			// 現在時刻の分が0（正時）の場合のみ処理を検討
-			if t.Minute() == 0 {
-				hour := t.Hour()
-				// 指定された時刻（6時, 12時, 19時）であればリマインダーを送信
-				if hour == 6 || hour == 12 || hour == 19 {
-					log.Println("✉️ " + "リマインド開始")
-					SendReminder(s)
-					log.Println("✅ " + "リマインド完了")
-				}
+// SendFocusEnded は終わった集中・休憩タイマーをDMで知らせます。
+// 送れたら通知済みにし、失敗したらリマインドと同じく間をあけて再試行します。
+func SendFocusEnded(ctx context.Context, s *discordgo.Session) error {
+	logger := logging.From(ctx)
+	notices, err := service.EndedFocusService(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, n := range notices {
+		if err := sendDM(s, n.UserID, n.Content); err != nil {
+			giveUp, recErr := service.FocusNoticeFailedService(ctx, n, time.Now())
+			logger.Error("タイマー終了通知失敗", "user_id", n.UserID, "attempt", n.Attempts, "give_up", giveUp, "error", err)
+			if recErr != nil {
+				logger.Error("タイマー終了通知の記録に失敗", "session_id", n.ID, "error", recErr)
 			}
+			continue
 		}
-	}()
+		if err := service.FocusNoticeSentService(ctx, n, time.Now()); err != nil {
+			logger.Error("タイマー終了通知の記録に失敗", "session_id", n.ID, "error", err)
+		}
+	}
+	return nil
 }
 
-// SendReminder は、リマインド対象の全ユーザーにメッセージを送信します。
-func SendReminder(s *discordgo.Session) {
-	reminders, err := service.FixedTimeReminder()
+// RunRollover は持ち越し日数を数えて結果を記録します。
+func RunRollover(ctx context.Context) error {
+	n, err := service.RolloverService(ctx)
 	if err != nil {
-		log.Printf("❌ リマインド取得エラー: %v", err)
-		return
+		return err
 	}
+	logging.From(ctx).Info("持ち越し処理完了", "tasks", n)
+	return nil
+}
 
-	if len(reminders) == 0 {
-		log.Println("⚠️ リマインド対象が0件です。送信スキップ")
-		return
+// SendDueReminders は送信時刻になったリマインドを送ります。
+// 送れたら送信済みにし、失敗したら間をあけて再試行します（送信済みの記録の前に落ちたときだけ2回送ることがあります）。
+func SendDueReminders(ctx context.Context, s *discordgo.Session) error {
+	logger := logging.From(ctx)
+	reminders, err := service.DueRemindersService(ctx, time.Now())
+	if err != nil {
+		return err
 	}
-
 	for _, reminder := range reminders {
-		// DMチャンネルを作成
-		channel, err := s.UserChannelCreate(reminder.UserID)
+		if err := sendDM(s, reminder.UserID, reminder.Content); err != nil {
+			giveUp, recErr := service.ReminderFailedService(ctx, reminder, time.Now())
+			logger.Error("リマインド送信失敗", "user_id", reminder.UserID, "attempt", reminder.Attempts, "give_up", giveUp, "error", err)
+			if recErr != nil {
+				logger.Error("リマインドの記録に失敗", "reminder_id", reminder.ID, "error", recErr)
+			}
+			continue
+		}
+		if err := service.ReminderSentService(ctx, reminder, time.Now()); err != nil {
+			logger.Error("リマインドの記録に失敗", "reminder_id", reminder.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// WakeSnoozedTasks はスヌーズが明けたタスクを通知します。
+func WakeSnoozedTasks(ctx context.Context, s *discordgo.Session) error {
+	logger := logging.From(ctx)
+	notices, err := service.WakeSnoozedService(ctx)
+	if err != nil {
+		return err
+	}
+	for _, notice := range notices {
+		if notice.ChannelID != "" {
+			err = sendMessage(s, notice.ChannelID, "", notice.Content, &discordgo.MessageAllowedMentions{})
+		} else {
+			err = sendDM(s, notice.UserID, notice.Content)
+		}
 		if err != nil {
-			log.Printf("⚠️ DMチャンネル取得失敗 userID=%s: %v", reminder.UserID, err)
-			continue // 次のユーザーへ
+			logger.Error("スヌーズ明け通知失敗", "user_id", notice.UserID, "channel_id", notice.ChannelID, "error", err)
 		}
+	}
+	logger.Info("スヌーズ解除完了", "tasks", len(notices))
+	return nil
+}
 
-		// DMを送信
-		_, err = s.ChannelMessageSend(channel.ID, reminder.Content)
+// SendDailyDigest は、投稿先が設定された全サーバーにダイジェストを投稿します。
+// 1件も投稿できなかったときだけエラーを返します（一部を投稿済みで再試行すると重複するため）。
+func SendDailyDigest(ctx context.Context, s *discordgo.Session) error {
+	logger := logging.From(ctx)
+	digests, err := service.DailyDigests(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, digest := range digests {
+		// メンバーへのメンションで通知が飛ばないようにする
+		err := sendMessage(s, digest.ChannelID, "", digest.Content, &discordgo.MessageAllowedMentions{})
 		if err != nil {
-			log.Printf("❌ リマインド送信失敗 userID=%s: %v", reminder.UserID, err)
+			logger.Error("ダイジェスト投稿失敗", "guild_id", digest.GuildID, "channel_id", digest.ChannelID, "error", err)
+			errs = append(errs, err)
 		}
 	}
+	if len(errs) > 0 && len(errs) == len(digests) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// SendReminder は、リマインド対象の全ユーザーにメッセージを送信します。
+// 1件も送信できなかったときだけエラーを返します（一部を送信済みで再試行すると重複するため）。
+func SendReminder(ctx context.Context, s *discordgo.Session) error {
+	logger := logging.From(ctx)
+	reminders, err := service.FixedTimeReminder(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if len(reminders) == 0 {
+		logger.Warn("リマインド対象が0件です。送信スキップ")
+		return nil
+	}
+
+	var errs []error
+	for _, reminder := range reminders {
+		// DMを送信（失敗しても次のユーザーへ）
+		if err := sendDM(s, reminder.UserID, reminder.Content); err != nil {
+			logger.Error("リマインド送信失敗", "user_id", reminder.UserID, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(reminders) {
+		return errors.Join(errs...)
+	}
+	return nil
 }
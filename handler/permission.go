@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"self-management-bot/config"
+	"self-management-bot/logging"
+	"self-management-bot/service"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Permission はコマンドの実行に必要な権限です。
+type Permission int
+
+const (
+	PermEveryone Permission = iota // 誰でも
+	PermManager                    // サーバー管理権限、または管理ロールを持つユーザ
+	PermBotAdmin                   // BOT_ADMIN_IDS に含まれるユーザ
+)
+
+// managerPermissions のいずれかを持つユーザは管理者として扱います。
+const managerPermissions = discordgo.PermissionAdministrator |
+	discordgo.PermissionManageServer |
+	discordgo.PermissionManageChannels
+
+// Perms はコマンドを実行したユーザが持つ権限です。
+type Perms struct {
+	Manager  bool
+	BotAdmin bool
+}
+
+// Has は p の権限を満たすかどうかを返します。
+func (p Perms) Has(perm Permission) bool {
+	switch perm {
+	case PermBotAdmin:
+		return p.BotAdmin
+	case PermManager:
+		return p.Manager || p.BotAdmin
+	default:
+		return true
+	}
+}
+
+// resolvePerms はDiscordのロールとサーバー権限からユーザの権限を求めます。
+func resolvePerms(s *discordgo.Session, m *discordgo.MessageCreate) Perms {
+	var perms Perms
+	if config.Cfg != nil {
+		perms.BotAdmin = slices.Contains(config.Cfg.BotAdminIDs, m.Author.ID)
+	}
+	if m.GuildID == "" {
+		return perms
+	}
+
+	p, err := s.State.MessagePermissions(m.Message)
+	if err != nil {
+		// Stateにキャッシュが無ければAPIから取得する
+		p, err = s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+	}
+	if err == nil && p&managerPermissions != 0 {
+		perms.Manager = true
+		return perms
+	}
+
+	if m.Member != nil && config.Cfg != nil {
+		for _, roleID := range m.Member.Roles {
+			role, err := s.State.Role(m.GuildID, roleID)
+			if err != nil {
+				continue
+			}
+			if slices.ContainsFunc(config.Cfg.ManagerRoles, func(name string) bool {
+				return strings.EqualFold(name, role.Name)
+			}) {
+				perms.Manager = true
+				break
+			}
+		}
+	}
+	return perms
+}
+
+// Authorize はハンドラ実行前に対象リストと権限を確定し、必要な権限が無ければ拒否します。
+func Authorize(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		ctx.Perms = resolvePerms(ctx.Session, ctx.Message)
+		cmd := ctx.Command
+
+		if cmd.Scoped {
+			scope, err := service.ResolveScope(ctx.Ctx, ctx.Message.GuildID, ctx.Message.ChannelID, ctx.UserID(), ctx.Team)
+			if err != nil {
+				ctx.ReplyError(err)
+				return
+			}
+			ctx.Scope = scope
+		}
+
+		required := cmd.Permission
+		if ctx.Scope.IsShared() && cmd.SharedPermission > required {
+			required = cmd.SharedPermission
+		}
+		if !ctx.Perms.Has(required) {
+			logging.From(ctx.Ctx).Warn("permission denied", "required", required)
+			ctx.Reply(ctx.T("common.forbidden"))
+			return
+		}
+		next(ctx)
+	}
+}
+
+// actor はサービス層に渡す実行者情報を返します。
+func (c *Context) actor() service.Actor {
+	return service.Actor{UserID: c.UserID(), Manager: c.Perms.Has(PermManager), ChannelID: c.Message.ChannelID}
+}
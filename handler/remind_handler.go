@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"self-management-bot/service"
+	"strings"
+	"time"
+)
+
+// HandleRemind は指定した時刻にタスクをDMでリマインドします（例: !remind 12 15:30、!remind 12 in 2h）。
+// 時刻の代わりに off を指定すると、そのタスクの未送信のリマインドを取り消します。
+func HandleRemind(ctx *Context) {
+	text := ctx.RestAfter(0)
+	if snoozeOffWords[strings.ToLower(text)] {
+		task, err := service.CancelRemindersService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0))
+		if err != nil {
+			ctx.ReplyError(err)
+			return
+		}
+		ctx.Reply(ctx.T("remind.cancelled", task.ID, task.Title))
+		return
+	}
+	at, err := service.ParseRemindTime(text, time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	task, err := service.SetReminderService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0), at)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("remind.success", at.Format("2006-01-02 15:04"), task.ID, task.Title))
+}
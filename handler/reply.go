@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// messageLimit はDiscordの1メッセージあたりの最大文字数です。
+	messageLimit = 2000
+	// embedDescriptionLimit は埋め込みの本文の最大文字数です。
+	embedDescriptionLimit = 4096
+	// maxReplyChunks を超えて分割される長さの本文は、ファイルとして添付します。
+	maxReplyChunks = 4
+	// fenceReserve は分割時にコードブロックを閉じ直す・開き直すために空けておく文字数です。
+	fenceReserve = 32
+
+	codeFence = "```"
+)
+
+// embedColor は埋め込みの左端の色です。
+const embedColor = 0x5865F2
+
+// fenceLangPattern はコードブロックの開始行の言語指定（"```go" の "go"）です。
+var fenceLangPattern = regexp.MustCompile(`^[A-Za-z0-9+#-]{1,16}$`)
+
+// sendMessage は content をチャンネルに送信します。
+// 2000文字を超える場合は行単位で分割し、分割しても長すぎる場合はファイルとして添付します。
+// allowed が nil でなければ、メンションによる通知をその設定に従って制限します。
+func sendMessage(s *discordgo.Session, channelID, mention, content string, allowed *discordgo.MessageAllowedMentions) error {
+	chunks := splitMessage(content, messageLimit-utf8.RuneCountInString(mention))
+	if len(chunks) > maxReplyChunks {
+		return sendAsFile(s, channelID, mention, content, allowed)
+	}
+	for i, chunk := range chunks {
+		if i == 0 {
+			chunk = mention + chunk
+		}
+		_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:         chunk,
+			AllowedMentions: allowed,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendWithComponents は content をボタンなどのコンポーネント付きで送信します。
+// 1メッセージに収まらない場合はコンポーネントを付けずに sendMessage と同じく分割して送ります。
+func sendWithComponents(s *discordgo.Session, channelID, mention, content string, components []discordgo.MessageComponent) error {
+	if utf8.RuneCountInString(mention+content) > messageLimit {
+		return sendMessage(s, channelID, mention, content, nil)
+	}
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:    mention + content,
+		Components: components,
+	})
+	return err
+}
+
+// sendAsFile は content をファイルとして添付します。コードブロックを含む場合は .md、それ以外は .txt です。
+func sendAsFile(s *discordgo.Session, channelID, mention, content string, allowed *discordgo.MessageAllowedMentions) error {
+	name := "reply.txt"
+	if strings.Contains(content, codeFence) {
+		name = "reply.md"
+	}
+	return sendFile(s, channelID, mention+"📎 "+name, name, "text/plain; charset=utf-8", []byte(content), allowed)
+}
+
+// sendFile は data を name という名前のファイルとして、message を添えて添付します。
+func sendFile(s *discordgo.Session, channelID, message, name, contentType string, data []byte, allowed *discordgo.MessageAllowedMentions) error {
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         message,
+		AllowedMentions: allowed,
+		Files: []*discordgo.File{{
+			Name:        name,
+			ContentType: contentType,
+			Reader:      bytes.NewReader(data),
+		}},
+	})
+	return err
+}
+
+// sendEmbed は本文を埋め込みで送信します。本文が埋め込みに収まらない場合は通常のメッセージとして送ります。
+func sendEmbed(s *discordgo.Session, channelID, mention, title, description string) error {
+	if utf8.RuneCountInString(description) > embedDescriptionLimit {
+		return sendMessage(s, channelID, mention, fmt.Sprintf("**%s**\n%s", title, description), nil)
+	}
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: strings.TrimSuffix(mention, "\n"),
+		Embed: &discordgo.MessageEmbed{
+			Title:       title,
+			Description: description,
+			Color:       embedColor,
+		},
+	})
+	return err
+}
+
+// splitMessage は content を limit 文字以内のメッセージに分割します。
+// 行の途中では区切らず（1行が長すぎる場合を除く）、コードブロックの途中で区切るときは
+// いったん閉じて次のメッセージで同じ言語指定で開き直します。
+func splitMessage(content string, limit int) []string {
+	if utf8.RuneCountInString(content) <= limit {
+		return []string{content}
+	}
+	var chunks []string
+	var cur strings.Builder
+	curLen := 0
+	fence := "" // 開いているコードブロックの開始（"```" や "```go"）。閉じていれば空
+
+	flush := func() {
+		text := cur.String()
+		if fence != "" {
+			if !strings.HasSuffix(text, "\n") {
+				text += "\n"
+			}
+			text += codeFence
+		}
+		chunks = append(chunks, text)
+		cur.Reset()
+		curLen = 0
+		if fence != "" {
+			cur.WriteString(fence + "\n")
+			curLen = utf8.RuneCountInString(fence) + 1
+		}
+	}
+
+	for _, line := range splitLongLines(content, limit-fenceReserve) {
+		n := utf8.RuneCountInString(line)
+		if curLen > 0 && curLen+n+len(codeFence)+1 > limit {
+			flush()
+		}
+		cur.WriteString(line)
+		curLen += n
+		if strings.Count(line, codeFence)%2 == 1 {
+			if fence == "" {
+				fence = fenceOpener(line)
+			} else {
+				fence = ""
+			}
+		}
+	}
+	if curLen > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// splitLongLines は content を改行を残したまま行に分け、max 文字を超える行はさらに区切ります。
+func splitLongLines(content string, max int) []string {
+	var lines []string
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		runes := []rune(line)
+		for len(runes) > max {
+			lines = append(lines, string(runes[:max]))
+			runes = runes[max:]
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
+
+// fenceOpener はコードブロックを開いた行から、開き直すときに使う開始（言語指定付き）を返します。
+func fenceOpener(line string) string {
+	i := strings.LastIndex(line, codeFence)
+	lang := strings.TrimSpace(line[i+len(codeFence):])
+	if fenceLangPattern.MatchString(lang) {
+		return codeFence + lang
+	}
+	return codeFence
+}
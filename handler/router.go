@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+	"self-management-bot/service"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// teamKeyword をコマンド名の直後に置くと、チャンネルの共有リストが対象になります。
+const teamKeyword = "@team"
+
+// ArgKind は引数の型です。
+type ArgKind int
+
+const (
+	ArgText ArgKind = iota // 任意の文字列（残りすべてを含む）
+	ArgInt                 // 整数
+	ArgID                  // タスクID（!search などに表示される "#123"。"#" は省略可）
+)
+
+// ArgSpec はコマンド引数の定義です。
+type ArgSpec struct {
+	Name     string // 翻訳キー（例: "arg.title"）
+	Kind     ArgKind
+	Required bool
+}
+
+// Command は登録されるコマンドの定義です。
+type Command struct {
+	Name     string   // 空白区切りで複数語も可（例: "confirm reset"）
+	Aliases  []string // 別名
+	Args     []ArgSpec
+	Category string // ヘルプの見出しの翻訳キー（例: "category.tasks"）
+	Handler  HandlerFunc
+
+	// Scoped が true なら、実行前に対象リスト（個人/共有）を Context.Scope に解決します。
+	Scoped bool
+	// Permission は実行に必要な権限、SharedPermission は共有リストを対象にする場合に追加で必要な権限です。
+	Permission       Permission
+	SharedPermission Permission
+}
+
+// Usage はヘルプに表示する書式です（例: "!add <タスク名> [P1~P4]"）。
+// 翻訳キーはコマンド名の空白を "_" にしたもの（"cmd.confirm_reset.usage" など）です。
+func (c *Command) Usage(lang string) string {
+	return i18n.T(lang, "cmd."+strings.ReplaceAll(c.Name, " ", "_")+".usage")
+}
+
+// Help はヘルプに表示する説明です。
+func (c *Command) Help(lang string) string {
+	return i18n.T(lang, "cmd."+strings.ReplaceAll(c.Name, " ", "_")+".help")
+}
+
+// Context はコマンド実行時の情報をまとめたものです。
+type Context struct {
+	// Ctx は相関ID付きのロガーを持ち、service/repository/client に引き回します。
+	Ctx      context.Context
+	Session  *discordgo.Session
+	Message  *discordgo.MessageCreate
+	Command  *Command
+	Invoked  string   // 実際に入力されたコマンド名
+	Args     []string // コマンド名以降を空白で区切ったもの（@team は除く）
+	Raw      string   // コマンド名以降の生の文字列（@team は除く）
+	Team     bool     // @team が指定されたか
+	Scope    repository.Scope
+	Perms    Perms
+	Settings service.Settings // サーバー/チャンネルの実効設定
+	Lang     string           // 返信に使う言語
+}
+
+// T は返信用の言語で key を翻訳します。
+func (c *Context) T(key string, args ...any) string {
+	return i18n.T(c.Lang, key, args...)
+}
+
+// UserID はコマンドを実行したユーザIDです。
+func (c *Context) UserID() string {
+	return c.Message.Author.ID
+}
+
+// replyTarget は返信先のチャンネルと、先頭に付けるメンションを返します。
+// 返信先がDMに設定されている場合はDMチャンネル（メンションなし）です。
+func (c *Context) replyTarget() (string, string, error) {
+	if c.Settings.ReplyMode == service.ReplyModeDM && c.Message.GuildID != "" {
+		channel, err := c.Session.UserChannelCreate(c.UserID())
+		if err != nil {
+			return "", "", fmt.Errorf("DMチャンネル取得失敗: %w", err)
+		}
+		return channel.ID, "", nil
+	}
+	return c.Message.ChannelID, fmt.Sprintf("<@%s>\n", c.UserID()), nil
+}
+
+// Reply は実行したユーザにメンション付きで返信します。
+// 返信先がDMに設定されている場合はDMで送ります。
+func (c *Context) Reply(message string) {
+	c.send(func(channelID, mention string) error {
+		return sendMessage(c.Session, channelID, mention, message, nil)
+	})
+}
+
+// ReplyEmbed は本文を埋め込みで返信します。Reply と同じく返信先の設定に従います。
+func (c *Context) ReplyEmbed(title, description string) {
+	c.send(func(channelID, mention string) error {
+		return sendEmbed(c.Session, channelID, mention, title, description)
+	})
+}
+
+// ReplyWithComponents はボタンなどのコンポーネント付きで返信します。
+func (c *Context) ReplyWithComponents(message string, components []discordgo.MessageComponent) {
+	c.send(func(channelID, mention string) error {
+		return sendWithComponents(c.Session, channelID, mention, message, components)
+	})
+}
+
+// ReplyFile は message を添えてファイルを添付して返信します。Reply と同じく返信先の設定に従います。
+func (c *Context) ReplyFile(message, name, contentType string, data []byte) {
+	c.send(func(channelID, mention string) error {
+		return sendFile(c.Session, channelID, mention+message, name, contentType, data, nil)
+	})
+}
+
+func (c *Context) send(fn func(channelID, mention string) error) {
+	channelID, mention, err := c.replyTarget()
+	if err == nil {
+		err = fn(channelID, mention)
+	}
+	if err != nil {
+		logging.From(c.Ctx).Error("Discord送信エラー", "error", err)
+	}
+}
+
+// ReplyError はサービス層のエラーを翻訳して返信します。
+func (c *Context) ReplyError(err error) {
+	c.Reply(c.T("common.error", i18n.Message(c.Lang, err)))
+}
+
+// IntArg は i 番目の引数を整数として返します（スキーマ検証済みである前提）。
+func (c *Context) IntArg(i int) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(c.Args[i], "#"))
+	return n
+}
+
+// RestAfter は i 番目までの引数を除いた残りの生の文字列です（改行を保ちます）。
+func (c *Context) RestAfter(i int) string {
+	rest := c.Raw
+	for _, arg := range c.Args[:i+1] {
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), arg)
+	}
+	return strings.TrimSpace(rest)
+}
+
+// HandlerFunc はコマンドの処理本体です。
+type HandlerFunc func(ctx *Context)
+
+// Middleware は HandlerFunc を包んで共通処理を差し込みます。
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Router はコマンドの登録と振り分けを行います。
+type Router struct {
+	commands    []*Command
+	index       map[string]*Command
+	middlewares []Middleware
+	maxWords    int
+}
+
+func NewRouter() *Router {
+	return &Router{index: map[string]*Command{}}
+}
+
+// Use はミドルウェアを追加します。先に追加したものほど外側で実行されます。
+func (r *Router) Use(mw ...Middleware) {
+	r.middlewares = append(r.middlewares, mw...)
+}
+
+// Register はコマンドを登録します。名前や別名が重複した場合は panic します。
+func (r *Router) Register(cmd *Command) {
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, ok := r.index[name]; ok {
+			panic(fmt.Sprintf("command %q is already registered", name))
+		}
+		r.index[name] = cmd
+		if n := len(strings.Fields(name)); n > r.maxWords {
+			r.maxWords = n
+		}
+	}
+	r.commands = append(r.commands, cmd)
+}
+
+// Commands は登録順のコマンド一覧を返します。
+func (r *Router) Commands() []*Command {
+	return r.commands
+}
+
+// match は入力に一致するコマンドを探します。
+// 複数語のコマンドを優先するため、語数の多い順に完全一致で照合します。
+func (r *Router) match(body string) (*Command, string, string) {
+	fields := strings.Fields(body)
+	for n := min(r.maxWords, len(fields)); n > 0; n-- {
+		name := strings.ToLower(strings.Join(fields[:n], " "))
+		if cmd, ok := r.index[name]; ok {
+			rest := body
+			for _, f := range fields[:n] {
+				rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), f))
+			}
+			return cmd, name, rest
+		}
+	}
+	return nil, "", ""
+}
+
+// Dispatch はメッセージを解析し、該当するコマンドをミドルウェア経由で実行します。
+func (r *Router) Dispatch(s *discordgo.Session, m *discordgo.MessageCreate, content string, settings service.Settings) {
+	if !strings.HasPrefix(content, settings.Prefix) {
+		return
+	}
+	cmd, invoked, rest := r.match(strings.TrimPrefix(content, settings.Prefix))
+	if cmd == nil {
+		return
+	}
+	team := false
+	if fields := strings.Fields(rest); len(fields) > 0 && strings.EqualFold(fields[0], teamKeyword) {
+		team = true
+		rest = strings.TrimSpace(rest[len(fields[0]):])
+	}
+	logCtx := logging.NewContext(context.Background(), logging.Fields{
+		UserID:    m.Author.ID,
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		Command:   cmd.Name,
+	})
+	ctx := &Context{
+		Ctx:      logCtx,
+		Session:  s,
+		Message:  m,
+		Command:  cmd,
+		Invoked:  invoked,
+		Args:     strings.Fields(rest),
+		Raw:      rest,
+		Team:     team,
+		Settings: settings,
+		Lang:     service.UserLanguage(logCtx, m.Author.ID, settings.Language),
+	}
+	h := validateArgs(cmd.Handler)
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		h = r.middlewares[i](h)
+	}
+	h(ctx)
+}
+
+// validateArgs は引数スキーマに従って入力を検証してからハンドラを呼びます。
+func validateArgs(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		for i, spec := range ctx.Command.Args {
+			if i >= len(ctx.Args) {
+				if spec.Required {
+					usage := usageWithPrefix(ctx.Command.Usage(ctx.Lang), ctx.Settings.Prefix)
+					ctx.Reply(ctx.T("common.arg_missing", ctx.T(spec.Name), usage))
+					return
+				}
+				break
+			}
+			if spec.Kind == ArgInt || spec.Kind == ArgID {
+				arg := ctx.Args[i]
+				if spec.Kind == ArgID {
+					arg = strings.TrimPrefix(arg, "#")
+				}
+				if _, err := strconv.Atoi(arg); err != nil {
+					ctx.Reply(ctx.T("common.not_number"))
+					return
+				}
+			}
+		}
+		next(ctx)
+	}
+}
+
+// HelpText は登録されたコマンドからヘルプ本文を組み立てます。
+// Usage は "!" 始まりで書かれているので、prefix に置き換えて表示します。
+func (r *Router) HelpText(prefix, lang string) string {
+	var categories []string
+	byCategory := map[string][]*Command{}
+	for _, cmd := range r.commands {
+		if _, ok := byCategory[cmd.Category]; !ok {
+			categories = append(categories, cmd.Category)
+		}
+		byCategory[cmd.Category] = append(byCategory[cmd.Category], cmd)
+	}
+	width := 0
+	for _, cmd := range r.commands {
+		width = max(width, displayWidth(usageWithPrefix(cmd.Usage(lang), prefix)))
+	}
+
+	var b strings.Builder
+	for i, category := range categories {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(i18n.T(lang, category) + "\n")
+		for _, cmd := range byCategory[category] {
+			usage := usageWithPrefix(cmd.Usage(lang), prefix)
+			pad := width - displayWidth(usage)
+			b.WriteString(usage + strings.Repeat(" ", pad) + " : " + cmd.Help(lang) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// displayWidth はコードブロック内での表示幅を返します（全角文字は2として数える）。
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		if r >= 0x1100 {
+			w += 2
+		} else {
+			w++
+		}
+	}
+	return w
+}
+
+func usageWithPrefix(usage, prefix string) string {
+	return prefix + strings.TrimPrefix(usage, "!")
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"fmt"
+	"self-management-bot/service"
+	"strings"
+)
+
+// HandleSearch は過去分も含めてタスクを検索し、タスクIDと作成日・完了日を表示します。
+func HandleSearch(ctx *Context) {
+	results, err := service.SearchTasksService(ctx.Ctx, ctx.Scope, ctx.Raw)
+	if err != nil {
+		ctx.Reply(ctx.T("search.failed"))
+		return
+	}
+	if len(results) == 0 {
+		ctx.Reply(ctx.T("search.empty", ctx.Raw))
+		return
+	}
+	var msg strings.Builder
+	msg.WriteString(ctx.T("search.header", ctx.Raw, len(results)))
+	if len(results) == service.SearchLimit {
+		msg.WriteString(ctx.T("search.limited", service.SearchLimit))
+	}
+	msg.WriteString("```")
+	for _, r := range results {
+		msg.WriteString(fmt.Sprintf("#%d %s %s %s\n", r.ID, statusEmoji[r.Status], priorityEmoji[r.PriorityID], r.Title))
+		if r.CompletedOn.Valid {
+			msg.WriteString(ctx.T("search.dates_completed", r.CreatedOn, r.CompletedOn.String))
+		} else {
+			msg.WriteString(ctx.T("search.dates", r.CreatedOn))
+		}
+	}
+	msg.WriteString("```")
+	ctx.Reply(msg.String())
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"self-management-bot/repository"
+	"self-management-bot/service"
+)
+
+// HandleStart はタスクを進行中にします。
+func HandleStart(ctx *Context) {
+	changeStatus(ctx, repository.StatusInProgress, "")
+}
+
+// HandleBlock はタスクを理由付きでブロック中にします。
+func HandleBlock(ctx *Context) {
+	changeStatus(ctx, repository.StatusBlocked, ctx.RestAfter(0))
+}
+
+// HandleWait はタスクを待ちにします。理由は省略できます。
+func HandleWait(ctx *Context) {
+	changeStatus(ctx, repository.StatusWaiting, ctx.RestAfter(0))
+}
+
+// HandleCancel はタスクをキャンセルします。削除と違い、タスクは履歴に残ります。
+func HandleCancel(ctx *Context) {
+	changeStatus(ctx, repository.StatusCancelled, "")
+}
+
+// HandleReopen は完了・キャンセルしたタスクをタスクIDで指定して未着手に戻します。
+func HandleReopen(ctx *Context) {
+	task, err := service.ReopenTaskService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0))
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("reopen.success", task.ID, task.Title))
+}
+
+// changeStatus は今日のリストの指定番号のタスクを status に変え、結果を返信します。
+func changeStatus(ctx *Context, status, reason string) {
+	task, err := service.ChangeStatusService(ctx.Ctx, ctx.Scope, ctx.actor(), ctx.IntArg(0), status, reason)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	label := ctx.T("status." + status)
+	if task.StatusReason != "" {
+		ctx.Reply(ctx.T("status.changed_reason", label, task.Title, task.StatusReason))
+		return
+	}
+	ctx.Reply(ctx.T("status.changed", label, task.Title))
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"self-management-bot/i18n"
+	"self-management-bot/service"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timesheetWeekWords / timesheetCSVWords は !timesheet の引数として受け付ける語です。
+var (
+	timesheetWeekWords = map[string]bool{"week": true, "今週": true, "週": true}
+	timesheetCSVWords  = map[string]bool{"csv": true}
+)
+
+// HandleTrackStart はタスクにかける時間の計測を始めます（例: !track start 12）。
+func HandleTrackStart(ctx *Context) {
+	started, err := service.StartTrackService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0), time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	var msg strings.Builder
+	if stopped := started.Stopped; stopped != nil {
+		msg.WriteString(ctx.T("track.switched", stopped.TaskID, stopped.Title, formatMinutes(ctx.Lang, stopped.Minutes)))
+	}
+	msg.WriteString(ctx.T("track.started", started.Task.ID, started.Task.Title))
+	ctx.Reply(msg.String())
+}
+
+// HandleTrackStop は計測を止めて記録します。
+func HandleTrackStop(ctx *Context) {
+	entry, err := service.StopTrackService(ctx.Ctx, ctx.UserID(), time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("track.stopped", entry.TaskID, entry.Title, formatMinutes(ctx.Lang, entry.Minutes)))
+}
+
+// HandleTrackAdd はかけた時間を手入力で記録します（例: !track add 12 45m、!track add 12 1h30m）。
+func HandleTrackAdd(ctx *Context) {
+	minutes, err := service.ParseMinutes(ctx.Args[1])
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	task, err := service.AddTrackService(ctx.Ctx, ctx.actor(), ctx.Message.GuildID, ctx.IntArg(0), minutes, time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	ctx.Reply(ctx.T("track.added", task.ID, task.Title, formatMinutes(ctx.Lang, minutes)))
+}
+
+// HandleTimesheet は今日（week なら今週）かけた時間を日・タスク・タグごとに集計します。csv を付けるとCSVファイルで返します。
+func HandleTimesheet(ctx *Context) {
+	week, asCSV := false, false
+	for _, arg := range ctx.Args {
+		lower := strings.ToLower(arg)
+		switch {
+		case timesheetWeekWords[lower]:
+			week = true
+		case timesheetCSVWords[lower]:
+			asCSV = true
+		default:
+			ctx.ReplyError(i18n.NewError("err.timesheet_arg", arg))
+			return
+		}
+	}
+	sheet, err := service.TimesheetService(ctx.Ctx, ctx.UserID(), week, time.Now())
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+	if asCSV {
+		data, err := timesheetCSV(sheet)
+		if err != nil {
+			ctx.ReplyError(err)
+			return
+		}
+		name := fmt.Sprintf("timesheet_%s_%s.csv", sheet.Since, sheet.Until)
+		ctx.ReplyFile(ctx.T("timesheet.csv", len(sheet.Entries)), name, "text/csv; charset=utf-8", data)
+		return
+	}
+	ctx.Reply(formatTimesheet(ctx.Lang, sheet, week))
+}
+
+// formatTimesheet は集計を日別・タスク別・タグ別に表示します。
+func formatTimesheet(lang string, sheet service.Timesheet, week bool) string {
+	var msg strings.Builder
+	period := i18n.T(lang, "timesheet.today", sheet.Until)
+	if week {
+		period = i18n.T(lang, "timesheet.week", sheet.Since, sheet.Until)
+	}
+	if len(sheet.Entries) == 0 {
+		msg.WriteString(i18n.T(lang, "timesheet.empty", period))
+	} else {
+		msg.WriteString(i18n.T(lang, "timesheet.header", period, formatMinutes(lang, sheet.Total)))
+		if week {
+			msg.WriteString(i18n.T(lang, "timesheet.by_day"))
+			for _, row := range sheet.ByDay {
+				msg.WriteString(fmt.Sprintf("%s: %s\n", row.Label, formatMinutes(lang, row.Minutes)))
+			}
+		}
+		msg.WriteString(i18n.T(lang, "timesheet.by_task"))
+		for _, row := range sheet.ByTask {
+			msg.WriteString(fmt.Sprintf("#%d %s: %s\n", row.TaskID, row.Label, formatMinutes(lang, row.Minutes)))
+		}
+		msg.WriteString(i18n.T(lang, "timesheet.by_tag"))
+		for _, row := range sheet.ByTag {
+			label := "#" + row.Label
+			if row.Label == "" {
+				label = i18n.T(lang, "timesheet.no_tag")
+			}
+			msg.WriteString(fmt.Sprintf("%s: %s\n", label, formatMinutes(lang, row.Minutes)))
+		}
+	}
+	if r := sheet.Running; r != nil {
+		msg.WriteString(i18n.T(lang, "timesheet.running", r.TaskID, r.Title, r.StartedAt))
+	}
+	return msg.String()
+}
+
+// timesheetCSV は記録1件を1行にしたCSVを作ります。Excelで文字化けしないよう先頭にBOMを付けます。
+// タイトルとタグはユーザが自由に書けるので、表計算ソフトで数式として実行されないよう csvCell でエスケープします。
+func timesheetCSV(sheet service.Timesheet) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\uFEFF")
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"date", "started_at", "task_id", "title", "tags", "minutes"})
+	for _, e := range sheet.Entries {
+		w.Write([]string{
+			e.Day, e.StartedAt, strconv.Itoa(e.TaskID), csvCell(e.Title),
+			csvCell(strings.Join(service.TaskTags(e.Title), " ")), strconv.Itoa(e.Minutes),
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// csvCell は =, +, -, @（とタブ・改行）で始まるセルの先頭に ' を付け、数式として解釈されないようにします。
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
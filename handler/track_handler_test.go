@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"self-management-bot/repository"
+	"self-management-bot/service"
+	"testing"
+)
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"=HYPERLINK(\"http://example.com\")", "'=HYPERLINK(\"http://example.com\")"},
+		{"+1+2", "'+1+2"},
+		{"-1+2", "'-1+2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"資料作成 =1", "資料作成 =1"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := csvCell(tt.in); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTimesheetCSVEscapesTitles(t *testing.T) {
+	sheet := service.Timesheet{Entries: []repository.TimeEntry{
+		{Day: "2026-10-19", StartedAt: "2026-10-19 09:00", TaskID: 1, Title: "=HYPERLINK(\"x\")", Minutes: 30},
+		{Day: "2026-10-19", StartedAt: "2026-10-19 10:00", TaskID: 2, Title: "+1", Minutes: 15},
+		{Day: "2026-10-19", StartedAt: "2026-10-19 11:00", TaskID: 3, Title: "-1", Minutes: 15},
+		{Day: "2026-10-19", StartedAt: "2026-10-19 12:00", TaskID: 4, Title: "@me #仕事", Minutes: 15},
+	}}
+	data, err := timesheetCSV(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\uFEFF")))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(sheet.Entries)+1 {
+		t.Fatalf("got %d records, want %d", len(records), len(sheet.Entries)+1)
+	}
+	for i, e := range sheet.Entries {
+		if got, want := records[i+1][3], "'"+e.Title; got != want {
+			t.Errorf("title = %q, want %q", got, want)
+		}
+	}
+}
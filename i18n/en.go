@@ -0,0 +1,390 @@
+package i18n
+
+// en は英語のメッセージです。
+var en = map[string]string{
+	// ヘルプの見出し
+	"category.tasks":  "✅ Tasks",
+	"category.habits": "🔁 Habits",
+	"category.reset":  "♻️ Reset tasks (careful)",
+	"category.team":   "👥 Shared lists",
+	"category.ai":     "🤖 AI",
+	"category.config": "⚙️ Settings",
+	"category.admin":  "🛡️ Bot admin",
+	"category.help":   "❓ Help",
+
+	// コマンドの書式と説明
+	"cmd.add.usage":            "!add [@team] <title> [@assignee] [P1~P4] [est:2h]",
+	"cmd.add.help":             "Add a task (@assignee assigns it to someone else)",
+	"cmd.assigned.usage":       "!assigned",
+	"cmd.assigned.help":        "List tasks you assigned to others",
+	"cmd.list.usage":           "!list [@team] [filters]",
+	"cmd.list.help":            "List tasks grouped by status (filter by P1 / doing, blocked, done, open, snoozed, all, ... / since:2026-10-01 / keywords)",
+	"cmd.search.usage":         "!search [@team] <keywords>",
+	"cmd.search.help":          "Search all tasks, including past ones",
+	"cmd.show.usage":           "!show <ID>",
+	"cmd.show.help":            "Show a task's details, notes, and status history (the #ID from !search etc.)",
+	"cmd.note.usage":           "!note <ID> <text>",
+	"cmd.note.help":            "Add a note to a task (multiple lines OK)",
+	"cmd.done.usage":           "!done [@team] <number>",
+	"cmd.done.help":            "Mark a task as done",
+	"cmd.start.usage":          "!start [@team] <number>",
+	"cmd.start.help":           "Mark a task as in progress",
+	"cmd.block.usage":          "!block [@team] <number> <reason>",
+	"cmd.block.help":           "Mark a task as blocked, with a reason",
+	"cmd.wait.usage":           "!wait [@team] <number> [reason]",
+	"cmd.wait.help":            "Mark a task as waiting on someone else",
+	"cmd.cancel.usage":         "!cancel [@team] <number>",
+	"cmd.cancel.help":          "Cancel a task (it stays in the history)",
+	"cmd.focus.usage":          "!focus <ID> [minutes]",
+	"cmd.focus.help":           "Start a focus timer for a task (25 minutes by default); you get a DM when it ends",
+	"cmd.focus_stop.usage":     "!focus stop",
+	"cmd.focus_stop.help":      "Stop the running focus or break timer",
+	"cmd.break.usage":          "!break [minutes]",
+	"cmd.break.help":           "Start a break timer (5 minutes by default)",
+	"cmd.track_start.usage":    "!track start <ID>",
+	"cmd.track_start.help":     "Start tracking time on a task (switches from the task being tracked, if any)",
+	"cmd.track_stop.usage":     "!track stop",
+	"cmd.track_stop.help":      "Stop tracking and log the time",
+	"cmd.track_add.usage":      "!track add <ID> <duration>",
+	"cmd.track_add.help":       "Log time spent by hand (e.g. 45m, 1h30m)",
+	"cmd.timesheet.usage":      "!timesheet [week] [csv]",
+	"cmd.timesheet.help":       "Time spent today (or this week with week) by day, task and tag; csv exports a file",
+	"cmd.estimates.usage":      "!estimates",
+	"cmd.estimates.help":       "Compare estimates with actual time for recently completed tasks, per priority",
+	"cmd.habit.usage":          "!habit [name]",
+	"cmd.habit.help":           "List habits and streaks; give a name to see its calendar",
+	"cmd.habit_add.usage":      "!habit add <name>",
+	"cmd.habit_add.help":       "Add a habit (e.g. workout, 30 min English)",
+	"cmd.habit_check.usage":    "!habit check <name>",
+	"cmd.habit_check.help":     "Record that you did the habit today",
+	"cmd.habit_delete.usage":   "!habit delete <name>",
+	"cmd.habit_delete.help":    "Delete a habit and its records",
+	"cmd.reopen.usage":         "!reopen <ID>",
+	"cmd.defer.usage":          "!defer <ID...> <tomorrow|YYYY-MM-DD>",
+	"cmd.defer.help":           "Defer tasks to a later date (not counted as carried over until then)",
+	"cmd.snooze.usage":         "!snooze <ID> <3d|2026-11-01|off>",
+	"cmd.snooze.help":          "Hide a task from lists and reminders until a date (it comes back that morning with a notice; off to undo)",
+	"cmd.remind.usage":         "!remind <ID> <15:30|in 2h|off>",
+	"cmd.remind.help":          "Get a DM about a task at a given time (off to cancel)",
+	"cmd.reopen.help":          "Return a done or cancelled task to to-do (the #ID from !search etc.)",
+	"cmd.edit.usage":           "!edit [@team] <number> <title> [P1~P4]",
+	"cmd.edit.help":            "Edit a task's title or priority",
+	"cmd.delete.usage":         "!delete [@team] <number>",
+	"cmd.delete.help":          "Delete a task",
+	"cmd.reset.usage":          "!reset [@team]",
+	"cmd.reset.help":           "Delete all of today's tasks (managers only for shared lists)",
+	"cmd.reset_all.usage":      "!reset all",
+	"cmd.reset_all.help":       "Delete every task (asks for confirmation)",
+	"cmd.confirm_reset.usage":  "!confirm reset",
+	"cmd.confirm_reset.help":   "Confirm deleting every task",
+	"cmd.team_on.usage":        "!team on",
+	"cmd.team_on.help":         "Turn this channel into a shared list (managers only)",
+	"cmd.team_off.usage":       "!team off",
+	"cmd.team_off.help":        "Turn off shared list mode (managers only)",
+	"cmd.digest_join.usage":    "!digest join",
+	"cmd.digest_join.help":     "Join the daily team digest",
+	"cmd.digest_leave.usage":   "!digest leave",
+	"cmd.digest_leave.help":    "Leave the daily team digest",
+	"cmd.chat.usage":           "!chat <message>",
+	"cmd.chat.help":            "Talk with the AI coach",
+	"cmd.config.usage":         "!config",
+	"cmd.config.help":          "Show this server's and channel's settings",
+	"cmd.config_prefix.usage":  "!config prefix <prefix>",
+	"cmd.config_prefix.help":   "Change the command prefix (managers only)",
+	"cmd.config_digest.usage":  "!config digest <#channel|here|off>",
+	"cmd.config_digest.help":   "Where to post the daily digest (managers only)",
+	"cmd.config_reply.usage":   "!config reply <channel|dm>",
+	"cmd.config_reply.help":    "Reply in the channel or by DM (managers only)",
+	"cmd.config_lang.usage":    "!config lang <ja|en>",
+	"cmd.config_lang.help":     "Default language for this server (managers only)",
+	"cmd.config_chat.usage":    "!config chat <on|off>",
+	"cmd.config_chat.help":     "Enable or disable !chat (managers only)",
+	"cmd.config_peptalk.usage": "!config peptalk <on|off>",
+	"cmd.config_peptalk.help":  "Add an AI pep talk to the digest (managers only)",
+	"cmd.config_channel.usage": "!config channel <reply|lang|chat> <value|reset>",
+	"cmd.config_channel.help":  "Override a setting for this channel only (managers only)",
+	"cmd.lang.usage":           "!lang [ja|en|reset]",
+	"cmd.lang.help":            "Change your display language",
+	"cmd.hours.usage":          "!hours [hours|reset]",
+	"cmd.hours.help":           "Set the hours you have per day (8 by default; the morning reminder warns when estimates exceed it)",
+	"cmd.broadcast.usage":      "!broadcast <message>",
+	"cmd.broadcast.help":       "DM an announcement to every user",
+	"cmd.stats.usage":          "!stats",
+	"cmd.stats.help":           "Show task totals for every user",
+	"cmd.help.usage":           "!help",
+	"cmd.help.help":            "Show this help",
+
+	// 引数名
+	"arg.title":    "a title",
+	"arg.number":   "a number",
+	"arg.content":  "a title",
+	"arg.message":  "a message",
+	"arg.value":    "a value",
+	"arg.key":      "a setting",
+	"arg.keyword":  "keywords",
+	"arg.task_id":  "a task ID",
+	"arg.note":     "a note",
+	"arg.reason":   "reason",
+	"arg.date":     "a date",
+	"arg.minutes":  "minutes",
+	"arg.duration": "duration",
+	"arg.period":   "period",
+	"arg.habit":    "habit name",
+	"arg.time":     "a time",
+
+	// 共通
+	"common.error":          "```❌ %s```",
+	"common.arg_missing":    "```⚠️ Please specify %s.\nExample: %s```",
+	"common.not_number":     "```❌ Please specify a number```",
+	"common.internal_error": "```❌ Something went wrong```",
+	"common.rate_limited":   "```⚠️ Too many commands. Please wait a moment and try again```",
+	"common.forbidden":      "```⛔️ You don't have permission to run this command```",
+	"common.fetch_failed":   "```❌ Failed to fetch tasks```",
+
+	// タスク操作
+	"add.empty":              "```⚠️ Please enter a task```",
+	"add.one_assignee":       "```⚠️ Please mention only one assignee```",
+	"add.failed":             "```❌ Failed to add the task```",
+	"add.assigned_notice":    "📨 <@%s> assigned you a task\n```%s %s```",
+	"add.assigned":           "```📨 Assigned to %s: %s priority: %d (%s)```",
+	"add.shared_suffix":      " (shared list)",
+	"add.success":            "```⭕️ Task added%s: %s priority: %d (%s)```",
+	"add.estimate":           "📐 Estimate: %s\n",
+	"list.empty":             "```📭 No tasks yet```",
+	"list.shared_header":     "Shared todo for <#%s>!\n",
+	"list.header":            "Today's todo!\n",
+	"list.filtered_header":   "Filtered todo!\n",
+	"list.no_match":          "```🔍 No tasks match those filters```",
+	"list.group.in_progress": "🏃 In progress\n",
+	"list.group.todo":        "📝 To do\n",
+	"list.group.waiting":     "⏳ Waiting\n",
+	"list.group.blocked":     "🚧 Blocked\n",
+	"list.group.done":        "✅ Done\n",
+	"list.group.cancelled":   "🚫 Cancelled\n",
+	"list.page":              "\n📄 Page %d / %d (%d tasks)\n",
+	"list.expired":           "⌛️ This list has expired. Please run !list again",
+	"list.not_owner":         "⚠️ Only the person who ran this list can page through it",
+	"done.rest_failed":       "```✅ Task done!\n⚠️ Failed to fetch the remaining tasks```",
+	"done.notice":            "✅ <@%s> finished a task you assigned\n```%s```",
+	"done.header":            "✅ Task done! Nice work!\n",
+	"done.remaining":         "\n📝 Remaining:\n",
+	"done.all_clear":         "\n🎉 Nothing left to do! Great job today!",
+	"delete.success":         "```⭕️ Task deleted```",
+	"edit.failed":            "```❌ Failed to edit the task: %s```",
+	"edit.success":           "```✅ Task updated```",
+	"reset.failed":           "```❌ Failed to reset today's tasks: %s```",
+	"reset.success":          "```✅ Deleted %d of today's tasks```",
+	"reset_all.confirm":      "```⚠️ Really delete every task, including past ones?\nType '%sconfirm reset' within 10 minutes to confirm.```",
+	"reset_all.expired":      "```⚠️ The confirmation for '%sreset all' expired. Please run it again.```",
+	"reset_all.failed":       "```❌ Failed to delete all tasks: %s```",
+	"reset_all.success":      "```✅ Deleted %d tasks```",
+	"assigned.empty":         "```📭 You haven't assigned any tasks```",
+	"assigned.header":        "Tasks you assigned!\n",
+	"chat.disabled":          "```⚠️ !chat is disabled on this server```",
+	"chat.title":             "🤖 AI coach",
+	"search.failed":          "```❌ Search failed```",
+	"search.empty":           "```🔍 No tasks match \"%s\"```",
+	"search.header":          "🔍 Results for \"%s\" (%d)\n",
+	"search.limited":         "Showing the top %d matches\n",
+	"search.dates":           "    created %s\n",
+	"search.dates_completed": "    created %s / done %s\n",
+	"note.added":             "```📝 Added a note to #%d %s```",
+	"defer.success":          "```⏭ Deferred %d tasks to %s\n",
+	"list.age":               " (day %d)",
+	"snooze.success":         "```😴 Snoozed #%d %s until %s```",
+	"snooze.cleared":         "```⏰ Unsnoozed #%d %s```",
+	"snooze.woke":            "⏰ A snoozed task is back on your list\n```#%d %s```",
+	"remind.success":         "```⏰ I'll remind you about #%[2]d %[3]s at %[1]s```",
+	"remind.cancelled":       "```🔕 Cancelled the reminders for #%d %s```",
+	"remind.due":             "⏰ Reminder\n```#%d %s```",
+	"focus.started":          "```🍅 Focusing on #%d %s for %d minutes (until %s). I'll DM you when it ends```",
+	"focus.stopped":          "```⏹ Stopped focusing on #%d %s (%s)```",
+	"focus.ended":            "🍅 Your focus on #%d %s (%d min) is over. Nice work!\nStart a break with `!break %d`",
+	"break.started":          "```☕ Taking a %d-minute break (until %s)```",
+	"break.stopped":          "```⏹ Stopped the break timer```",
+	"break.ended":            "☕ Your %d-minute break is over. Start the next one with `!focus <ID> %d`",
+	"list.focus_today":       "\n🍅 Focused today: %s\n",
+	"show.focus":             "Focused: %s in total\n",
+	"show.estimate":          "Estimate: %s\n",
+	"track.started":          "```⏱ Started tracking #%d %s. Stop with !track stop```",
+	"track.switched":         "⏹ Stopped tracking #%d %s (%s)\n",
+	"track.stopped":          "```⏹ Stopped tracking #%d %s (%s)```",
+	"track.added":            "```📝 Logged %[3]s on #%[1]d %[2]s```",
+	"timesheet.today":        "today (%s)",
+	"timesheet.week":         "this week (%s to %s)",
+	"timesheet.header":       "⏱ Time logged %s: %s in total\n",
+	"timesheet.empty":        "⏱ No time logged %s yet\n",
+	"timesheet.by_day":       "\n▼By day\n",
+	"timesheet.by_task":      "\n▼By task\n",
+	"timesheet.by_tag":       "\n▼By tag\n",
+	"timesheet.no_tag":       "(no tag)",
+	"timesheet.running":      "\n⏺ Tracking: #%d %s (since %s)\n",
+	"timesheet.csv":          "📎 Exported %d entries as CSV",
+	"estimates.header":       "📐 Estimates vs actuals (tasks completed since %s)\n",
+	"estimates.empty":        "Nothing to compare yet. Add an estimate like `!add Write slides P2 est:2h` and log time with `!track`\n",
+	"estimates.priority":     "%s P%d",
+	"estimates.total":        "Total",
+	"estimates.row":          "%s: %d tasks, estimated %s → actual %s (%d%%)\n",
+	"estimates.unknown":      "* %d tasks without a known actual time are left out (log time with `!track` or `!focus`, or `!start` a task before completing it)\n",
+	"hours.current":          "```⏰ Hours per day: %s```",
+	"hours.updated":          "```✅ Hours per day set to %s```",
+	"habit.header":           "🔁 **Habits**\n",
+	"habit.line":             "%s %s — 🔥 %d-day streak (best %d)\n",
+	"habit.empty":            "No habits yet. Add one like `!habit add workout`",
+	"habit.added":            "```🔁 Added the habit \"%s\". Record the days you do it with !habit check```",
+	"habit.checked":          "```✅ Recorded %s! 🔥 %d-day streak```",
+	"habit.record":           "🏆 That's your personal best!",
+	"habit.deleted":          "```🗑 Deleted the habit \"%s\"```",
+	"habit.heatmap_header":   "📅 **%s** (last %d weeks)\n",
+	"habit.weekdays":         "Mo,Tu,We,Th,Fr,Sa,Su",
+	"duration.minutes":       "%dm",
+	"duration.hours_minutes": "%dh%02dm",
+	"reopen.success":         "```↩️ Reopened #%d %s```",
+	"show.status":            "Status: %s / Priority: %s P%d\n",
+	"show.list_personal":     "List: personal\n",
+	"show.list_shared":       "List: shared list in <#%s>\n",
+	"show.created":           "Created: %s (👤 %s)\n",
+	"show.assignee":          "Assignee: %s\n",
+	"show.completed":         "Done: %s (✅ %s)\n",
+	"show.cancelled":         "Cancelled: %s (🚫 %s)\n",
+	"show.snoozed":           "Snoozed until %s\n",
+	"show.reminders":         "Reminders: %s\n",
+	"show.reason":            "Reason: %s\n",
+	"show.notes":             "\n📝 **Notes**\n",
+	"show.no_notes":          "\n📝 No notes yet (add one with `%snote %d <text>`)\n",
+	"show.history":           "\n📜 **Status history**\n",
+	"status.todo":            "📝 to do",
+	"status.in_progress":     "🏃 in progress",
+	"status.blocked":         "🚧 blocked",
+	"status.waiting":         "⏳ waiting",
+	"status.done":            "✅ done",
+	"status.cancelled":       "🚫 cancelled",
+	"status.changed":         "```Changed to %s: %s```",
+	"status.changed_reason":  "```Changed to %s: %s\nReason: %s```",
+	"status.reason_required": "```⚠️ Please give a reason for the block```",
+
+	// 共有リスト・ダイジェスト
+	"team.on":           "```✅ This channel is now a shared list. %[1]sadd / %[1]slist and friends now work on the channel's list```",
+	"team.off":          "```✅ Shared list mode turned off (shared tasks are still available with %slist @team)```",
+	"digest.joined":     "```✅ Joined the daily digest. Every morning yesterday's completed tasks and today's P1 tasks will be shared```",
+	"digest.no_channel": "\n⚠️ No digest channel is set (a manager can set one with `%sconfig digest #channel`)",
+	"digest.left":       "```✅ Left the daily digest```",
+	"digest.header":     "☀️ **Good morning! Here's today's team digest**\n",
+	"digest.member":     "\n👤 <@%s>\n",
+	"digest.done":       "✅ Done yesterday: %s\n",
+	"digest.p1":         "🔴 P1 today: %s\n",
+	"digest.none":       "none",
+	"digest.separator":  ", ",
+
+	// 設定
+	"config.guild_only":     "```⚠️ Settings are only available in server channels```",
+	"config.unset":          "(not set)",
+	"config.show":           "⚙️ Current settings\nPrefix: `%s`\nDigest channel: %s (pep talk: `%s`)\nReplies: `%s`\nLanguage: `%s`\n!chat: `%s`",
+	"config.digest_invalid": "```⚠️ Mention a channel (#channel) or use here / off```",
+	"config.updated":        "```✅ Settings updated```",
+	"lang.current":          "```🌐 Current language: %s (available: %s)```",
+	"lang.updated":          "```✅ Language set to %s```",
+	"lang.reset":            "```✅ Language preference cleared (server and Discord settings apply)```",
+
+	// Bot管理
+	"broadcast.fetch_failed": "```❌ Failed to fetch recipients```",
+	"broadcast.header":       "📢 Announcement\n",
+	"broadcast.sent":         "```📢 Sent to %d / %d users```",
+	"stats.failed":           "```❌ Failed to compute stats```",
+	"stats.empty":            "```📭 No users yet```",
+	"stats.header":           "📊 Stats for all users (%d)\n",
+	"stats.columns":          "User | Pending | Done | Done today\n",
+	"stats.more":             "…and %d more\n",
+	"help.title":             "**📋 Self-Management Bot commands**\nUse these commands to manage your tasks and talk with the AI!\n\n",
+
+	// サービス層のエラー
+	"err.fetch_tasks":          "Failed to fetch tasks",
+	"err.no_tasks":             "There are no tasks yet",
+	"err.no_such_task":         "No task with that number",
+	"err.shared_edit":          "Only the creator, the assignee, or a manager can edit a shared task",
+	"err.shared_delete":        "Only the creator, the assignee, or a manager can delete a shared task",
+	"err.shared_cancel":        "Only the creator, the assignee, or a manager can cancel a shared task",
+	"err.already_in_status":    "The task is already \"%s\"",
+	"err.invalid_transition":   "A task that is \"%s\" cannot become \"%s\"",
+	"err.shared_guild_only":    "Shared lists are only available in server channels",
+	"err.channel_list_fetch":   "Failed to fetch channel settings",
+	"err.settings_guild_only":  "Settings can only be changed in server channels",
+	"err.unknown_setting":      "Unknown setting: %s",
+	"err.setting_not_channel":  "%s can't be set per channel",
+	"err.prefix_invalid":       "The prefix must be at most 3 characters with no spaces",
+	"err.reply_invalid":        "reply must be channel or dm",
+	"err.lang_invalid":         "Language must be one of %s",
+	"err.onoff_invalid":        "%s must be on or off",
+	"err.settings_save":        "Failed to save settings",
+	"err.digest_guild_only":    "The digest is only available in server channels",
+	"err.chat_pending_fetch":   "Failed to fetch your tasks (pending)",
+	"err.chat_notes_fetch":     "Failed to fetch your task notes",
+	"err.chat_completed_fetch": "Failed to fetch your tasks (completed)",
+	"err.chat_llm":             "Failed to get a response (LLM)",
+	"err.task_not_found":       "Task #%d not found",
+	"err.not_closed":           "Task #%d is not done or cancelled",
+	"err.status_changed":       "The task's status was changed by someone else. Please check it again",
+	"err.status_save":          "Failed to change the status",
+	"err.note_save":            "Failed to save the note",
+	"err.invalid_future_date":  "Give the date as tomorrow, 3d (in 3 days), 2w (in 2 weeks), or YYYY-MM-DD: %s",
+	"err.date_not_future":      "The date must be tomorrow or later: %s",
+	"err.defer_closed":         "Task #%d is already finished",
+	"err.not_snoozed":          "Task #%d is not snoozed",
+	"err.snooze_save":          "Failed to save the snooze",
+	"err.invalid_remind_time":  "Give the time as 15:30 or in 2h / in 30m: %s",
+	"err.remind_save":          "Failed to save the reminder",
+	"err.no_reminders":         "Task #%d has no reminders to cancel",
+	"err.focus_minutes":        "Give the minutes as 1 to %d",
+	"err.focus_running":        "A timer is already running. Stop it with !focus stop",
+	"err.focus_not_running":    "No timer is running",
+	"err.focus_save":           "Failed to save the timer",
+	"err.invalid_duration":     "Give the duration like 45m, 1h30m or 90 (minutes): %s",
+	"err.track_minutes":        "Give a duration between 1 minute and %d hours",
+	"err.track_already":        "Task #%d is already being tracked",
+	"err.track_not_running":    "No task is being tracked",
+	"err.track_save":           "Failed to log the time",
+	"err.timesheet_arg":        "Use week or csv: %s",
+	"err.hours_range":          "Give a positive number of hours up to 24",
+	"err.habit_name":           "Give the habit a name of 1 to %d characters",
+	"err.habit_exists":         "You already have a habit named \"%s\"",
+	"err.habit_not_found":      "No habit named \"%s\" (see !habit for the list)",
+	"err.habit_checked":        "\"%s\" is already recorded for today",
+	"err.habit_save":           "Failed to save the habit",
+	"err.habit_fetch":          "Failed to load habits",
+	"err.defer_save":           "Failed to save the deferral",
+	"err.invalid_date":         "Dates must be in YYYY-MM-DD format: %s",
+
+	// LLMへのプロンプト
+	"prompt.answer_language":         "Please answer in English.\n",
+	"prompt.chat.role":               "You are a coach who helps people manage themselves.\n\n",
+	"prompt.chat.pending":            "[Pending tasks]\n",
+	"prompt.chat.pending_none":       "(no pending tasks)\n",
+	"prompt.chat.completed":          "\n[Recently completed tasks]\n",
+	"prompt.chat.completed_none":     "(no completed tasks)\n",
+	"prompt.chat.note":               "  Note: %s\n",
+	"prompt.chat.question":           "\n[User's question]\n",
+	"prompt.chat.instruction":        "\nGive advice based on the above.",
+	"prompt.reminder.role":           "You are a professional coach who helps people manage themselves.\n",
+	"prompt.reminder.goal":           "Based on how yesterday's tasks went, give positive and practical advice so the user can start today on a good note.\n",
+	"prompt.reminder.rules":          "Follow these rules:\n- Briefly and positively look back on what was achieved yesterday (if anything was completed)\n- If tasks were left unfinished, suggest how to make use of them today\n- Give 1 to 3 pieces of simple, actionable advice\n\n",
+	"prompt.reminder.status":         "[Yesterday's tasks]\n",
+	"prompt.reminder.completed":      "▼ Completed:\n",
+	"prompt.reminder.pending":        "▼ Not completed:\n",
+	"prompt.reminder.none_done":      "▼ Completed:\n(nothing completed)\n",
+	"prompt.reminder.none_pending":   "▼ Not completed:\n(nothing left)\n",
+	"prompt.reminder.chronic":        "▼Tasks carried over for days:\n",
+	"prompt.reminder.chronic_item":   "- %s (day %d, deferred %d times)\n",
+	"prompt.reminder.chronic_advice": "* For tasks that keep getting carried over, without blaming, suggest one concrete step such as splitting it up, doing it first thing today, or deciding to drop it\n",
+	"prompt.reminder.focus":          "▼Focused time yesterday: %d min\n",
+	"prompt.reminder.estimate":       "▼Estimated total of today's open tasks: %d min (hours available: %d min, tasks without an estimate: %d)\n",
+	"prompt.reminder.overcommit":     "* The estimates exceed the available time by %d min. Clearly warn the user to narrow down today's tasks or postpone some (!defer)\n",
+	"prompt.reminder.habits":         "▼Habits (done yesterday?, current streak):\n",
+	"prompt.reminder.habit_item":     "- %s: %s (%d-day streak)\n",
+	"prompt.reminder.habit_done":     "done",
+	"prompt.reminder.habit_missed":   "not done",
+	"prompt.reminder.instruction":    "\nUsing this information, write a message to help the user start today positively.\n",
+	"prompt.peptalk.role":            "You are a coach who cheers on a team.\n",
+	"prompt.peptalk.goal":            "Based on the team's status below, write a 2-3 sentence pep talk to start the day positively.\nDon't mention individual names; address the whole team.\n\n",
+	"prompt.peptalk.status":          "[Team status]\n",
+	"prompt.peptalk.member":          "- Member %d: %d done yesterday / %d P1 today\n",
+}
@@ -0,0 +1,140 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	Japanese = "ja"
+	English  = "en"
+
+	// Default は翻訳が見つからないときに使う言語です。
+	Default = Japanese
+)
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]string{
+		Japanese: ja,
+		English:  en,
+	}
+)
+
+// Register は言語を追加します。既にある言語なら messages で上書きします。
+func Register(lang string, messages map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = map[string]string{}
+		catalogs[lang] = catalog
+	}
+	for k, v := range messages {
+		catalog[k] = v
+	}
+}
+
+// Supported は利用できる言語をソートして返します。
+func Supported() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsSupported は lang が利用できる言語かどうかを返します。
+func IsSupported(lang string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// T は key の翻訳を返します。lang に無ければ Default、それも無ければ key をそのまま返します。
+// args があれば fmt.Sprintf で埋め込みます。
+func T(lang, key string, args ...any) string {
+	mu.RLock()
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	mu.RUnlock()
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// FromDiscordLocale はDiscordのロケール（"en-US", "ja" など）を対応言語に変換します。
+// 対応していなければ空文字を返します。
+func FromDiscordLocale(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	if IsSupported(lang) {
+		return lang
+	}
+	return ""
+}
+
+// Key を Error の引数に渡すと、表示するときにその言語で翻訳されます。
+type Key string
+
+// Error はユーザに表示する前に翻訳されるエラーです。
+type Error struct {
+	Key  string
+	Args []any
+	Err  error // 原因となったエラー（あれば表示時に付け足す）
+}
+
+// NewError は翻訳キー付きのエラーを作ります。
+func NewError(key string, args ...any) error {
+	return &Error{Key: key, Args: args}
+}
+
+// WrapError は err を原因とする翻訳キー付きのエラーを作ります。
+func WrapError(err error, key string, args ...any) error {
+	return &Error{Key: key, Args: args, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message(Default)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Message は lang で翻訳したエラーメッセージを返します。
+func (e *Error) Message(lang string) string {
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		if key, ok := arg.(Key); ok {
+			arg = T(lang, string(key))
+		}
+		args[i] = arg
+	}
+	msg := T(lang, e.Key, args...)
+	if e.Err != nil {
+		msg += ": " + Message(lang, e.Err)
+	}
+	return msg
+}
+
+// Message は err をユーザ向けの文字列にします。翻訳キー付きでなければ err.Error() です。
+func Message(lang string, err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Message(lang)
+	}
+	return err.Error()
+}
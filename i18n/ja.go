@@ -0,0 +1,390 @@
+package i18n
+
+// ja は日本語のメッセージです。キーが見つからない場合の既定値にもなります。
+var ja = map[string]string{
+	// ヘルプの見出し
+	"category.tasks":  "✅ タスク管理",
+	"category.habits": "🔁 習慣",
+	"category.reset":  "♻️ タスク全削除（慎重に）",
+	"category.team":   "👥 共有リスト",
+	"category.ai":     "🤖 AI機能",
+	"category.config": "⚙️ 設定",
+	"category.admin":  "🛡️ Bot管理",
+	"category.help":   "❓ ヘルプ",
+
+	// コマンドの書式と説明
+	"cmd.add.usage":            "!add [@team] <タスク名> [@担当者] [P1~P4] [est:2h]",
+	"cmd.add.help":             "タスクを追加（@担当者 で他の人に割り当て）",
+	"cmd.assigned.usage":       "!assigned",
+	"cmd.assigned.help":        "自分が割り当てたタスクを一覧表示",
+	"cmd.list.usage":           "!list [@team] [条件]",
+	"cmd.list.help":            "タスクを状態ごとに一覧表示（P1 / doing・blocked・done・open・snoozed・all など / since:2026-10-01 / キーワードで絞り込み）",
+	"cmd.search.usage":         "!search [@team] <キーワード>",
+	"cmd.search.help":          "過去分も含めてタスクを検索",
+	"cmd.show.usage":           "!show <ID>",
+	"cmd.show.help":            "タスクの詳細・メモ・状態の履歴を表示（IDは !search などの #番号）",
+	"cmd.note.usage":           "!note <ID> <メモ>",
+	"cmd.note.help":            "タスクにメモを追記（複数行可）",
+	"cmd.done.usage":           "!done [@team] <番号>",
+	"cmd.done.help":            "指定タスクを完了扱いに",
+	"cmd.start.usage":          "!start [@team] <番号>",
+	"cmd.start.help":           "指定タスクを進行中に",
+	"cmd.block.usage":          "!block [@team] <番号> <理由>",
+	"cmd.block.help":           "指定タスクをブロック中に（理由を記録）",
+	"cmd.wait.usage":           "!wait [@team] <番号> [理由]",
+	"cmd.wait.help":            "指定タスクを返事・外部待ちに",
+	"cmd.cancel.usage":         "!cancel [@team] <番号>",
+	"cmd.cancel.help":          "指定タスクをキャンセル（履歴には残ります）",
+	"cmd.focus.usage":          "!focus <ID> [分]",
+	"cmd.focus.help":           "タスクに集中するタイマー（既定25分）。終わったらDMで知らせます",
+	"cmd.focus_stop.usage":     "!focus stop",
+	"cmd.focus_stop.help":      "実行中の集中・休憩タイマーを止める",
+	"cmd.break.usage":          "!break [分]",
+	"cmd.break.help":           "休憩タイマー（既定5分）",
+	"cmd.track_start.usage":    "!track start <ID>",
+	"cmd.track_start.help":     "タスクにかける時間の計測を始める（別のタスクを計測中なら止めて切り替え）",
+	"cmd.track_stop.usage":     "!track stop",
+	"cmd.track_stop.help":      "計測を止めて記録する",
+	"cmd.track_add.usage":      "!track add <ID> <時間>",
+	"cmd.track_add.help":       "かけた時間を手入力で記録する（例: 45m、1h30m）",
+	"cmd.timesheet.usage":      "!timesheet [week] [csv]",
+	"cmd.timesheet.help":       "今日（week で今週）かけた時間を日・タスク・タグごとに集計。csv でファイル出力",
+	"cmd.estimates.usage":      "!estimates",
+	"cmd.estimates.help":       "最近完了したタスクの見積もりと実績を優先度ごとに比べる",
+	"cmd.habit.usage":          "!habit [名前]",
+	"cmd.habit.help":           "習慣の一覧と連続日数。名前を指定するとカレンダーを表示",
+	"cmd.habit_add.usage":      "!habit add <名前>",
+	"cmd.habit_add.help":       "習慣を追加（例: 筋トレ、英語30分）",
+	"cmd.habit_check.usage":    "!habit check <名前>",
+	"cmd.habit_check.help":     "今日その習慣をやったと記録",
+	"cmd.habit_delete.usage":   "!habit delete <名前>",
+	"cmd.habit_delete.help":    "習慣を記録ごと削除",
+	"cmd.reopen.usage":         "!reopen <ID>",
+	"cmd.defer.usage":          "!defer <ID...> <tomorrow|YYYY-MM-DD>",
+	"cmd.defer.help":           "タスクをまとめて後回しに（その日までは持ち越し日数を数えない）",
+	"cmd.snooze.usage":         "!snooze <ID> <3d|2026-11-01|off>",
+	"cmd.snooze.help":          "指定日まで一覧・リマインドから隠す（その日の朝に通知して再表示。off で解除）",
+	"cmd.remind.usage":         "!remind <ID> <15:30|in 2h|off>",
+	"cmd.remind.help":          "指定した時刻にタスクをDMでリマインド（off で取り消し）",
+	"cmd.reopen.help":          "完了・キャンセルしたタスクを未着手に戻す（IDは !search などの #番号）",
+	"cmd.edit.usage":           "!edit [@team] <番号> <内容> [P1~P4]",
+	"cmd.edit.help":            "内容や優先度を編集",
+	"cmd.delete.usage":         "!delete [@team] <番号>",
+	"cmd.delete.help":          "指定タスクを削除",
+	"cmd.reset.usage":          "!reset [@team]",
+	"cmd.reset.help":           "今日のタスクを全削除（共有リストは管理者のみ）",
+	"cmd.reset_all.usage":      "!reset all",
+	"cmd.reset_all.help":       "全タスクを削除（確認付き）",
+	"cmd.confirm_reset.usage":  "!confirm reset",
+	"cmd.confirm_reset.help":   "全削除を確定",
+	"cmd.team_on.usage":        "!team on",
+	"cmd.team_on.help":         "このチャンネルを共有リストモードに（管理者のみ）",
+	"cmd.team_off.usage":       "!team off",
+	"cmd.team_off.help":        "共有リストモードを解除（管理者のみ）",
+	"cmd.digest_join.usage":    "!digest join",
+	"cmd.digest_join.help":     "毎朝のチームダイジェストに参加",
+	"cmd.digest_leave.usage":   "!digest leave",
+	"cmd.digest_leave.help":    "チームダイジェストから抜ける",
+	"cmd.chat.usage":           "!chat <メッセージ>",
+	"cmd.chat.help":            "AIと会話（モチベ維持や相談）",
+	"cmd.config.usage":         "!config",
+	"cmd.config.help":          "このサーバー/チャンネルの設定を表示",
+	"cmd.config_prefix.usage":  "!config prefix <接頭辞>",
+	"cmd.config_prefix.help":   "コマンドの接頭辞を変更（管理者のみ）",
+	"cmd.config_digest.usage":  "!config digest <#チャンネル|here|off>",
+	"cmd.config_digest.help":   "デイリーダイジェストの投稿先（管理者のみ）",
+	"cmd.config_reply.usage":   "!config reply <channel|dm>",
+	"cmd.config_reply.help":    "返信先（チャンネル/DM）（管理者のみ）",
+	"cmd.config_lang.usage":    "!config lang <ja|en>",
+	"cmd.config_lang.help":     "サーバーの既定の言語（管理者のみ）",
+	"cmd.config_chat.usage":    "!config chat <on|off>",
+	"cmd.config_chat.help":     "!chat の有効/無効（管理者のみ）",
+	"cmd.config_peptalk.usage": "!config peptalk <on|off>",
+	"cmd.config_peptalk.help":  "ダイジェストにAIの応援メッセージを付ける（管理者のみ）",
+	"cmd.config_channel.usage": "!config channel <reply|lang|chat> <値|reset>",
+	"cmd.config_channel.help":  "このチャンネルだけ設定を上書き（管理者のみ）",
+	"cmd.lang.usage":           "!lang [ja|en|reset]",
+	"cmd.lang.help":            "自分の表示言語を変更",
+	"cmd.hours.usage":          "!hours [時間|reset]",
+	"cmd.hours.help":           "1日に使える時間を変更（既定8時間。見積もりの合計が超えると毎朝のリマインドで注意）",
+	"cmd.broadcast.usage":      "!broadcast <メッセージ>",
+	"cmd.broadcast.help":       "全ユーザにお知らせをDM",
+	"cmd.stats.usage":          "!stats",
+	"cmd.stats.help":           "全ユーザのタスク集計を表示",
+	"cmd.help.usage":           "!help",
+	"cmd.help.help":            "このヘルプを再表示",
+
+	// 引数名
+	"arg.title":    "タスク名",
+	"arg.number":   "番号",
+	"arg.content":  "内容",
+	"arg.message":  "メッセージ",
+	"arg.value":    "値",
+	"arg.key":      "項目",
+	"arg.keyword":  "キーワード",
+	"arg.task_id":  "タスクID",
+	"arg.note":     "メモ",
+	"arg.reason":   "理由",
+	"arg.date":     "日付",
+	"arg.minutes":  "分",
+	"arg.duration": "時間",
+	"arg.period":   "期間",
+	"arg.habit":    "習慣の名前",
+	"arg.time":     "時刻",
+
+	// 共通
+	"common.error":          "```❌ %s```",
+	"common.arg_missing":    "```⚠️ %s を指定してください。\n例: %s```",
+	"common.not_number":     "```❌ 数字を指定してください```",
+	"common.internal_error": "```❌ 内部エラーが発生しました```",
+	"common.rate_limited":   "```⚠️ コマンドの実行が多すぎます。少し待ってから再実行してください```",
+	"common.forbidden":      "```⛔️ このコマンドを実行する権限がありません```",
+	"common.fetch_failed":   "```❌ タスク取得失敗```",
+
+	// タスク操作
+	"add.empty":              "```⚠️ タスク内容を追加してください```",
+	"add.one_assignee":       "```⚠️ 担当者は1人だけ指定してください```",
+	"add.failed":             "```❌ タスク登録失敗```",
+	"add.assigned_notice":    "📨 <@%s> さんからタスクが割り当てられました\n```%s %s```",
+	"add.assigned":           "```📨 %s さんにタスクを割り当てました: %s 優先度： %d (%s)```",
+	"add.shared_suffix":      "（共有リスト）",
+	"add.success":            "```⭕️ タスク追加%s: %s 優先度： %d (%s)```",
+	"add.estimate":           "📐 見積もり: %s\n",
+	"list.empty":             "```📭 タスクが登録されていません```",
+	"list.shared_header":     "<#%s> の共有Todoです！\n",
+	"list.header":            "今日のTodoです！\n",
+	"list.filtered_header":   "絞り込んだTodoです！\n",
+	"list.no_match":          "```🔍 条件に一致するタスクはありません```",
+	"list.group.in_progress": "🏃 進行中\n",
+	"list.group.todo":        "📝 未着手\n",
+	"list.group.waiting":     "⏳ 待ち\n",
+	"list.group.blocked":     "🚧 ブロック中\n",
+	"list.group.done":        "✅ 完了\n",
+	"list.group.cancelled":   "🚫 キャンセル\n",
+	"list.page":              "\n📄 %d / %d ページ（全 %d 件）\n",
+	"list.expired":           "⌛️ このリストの操作期限が切れました。もう一度 !list を実行してください",
+	"list.not_owner":         "⚠️ このリストを操作できるのは実行した人だけです",
+	"done.rest_failed":       "```✅ タスク完了！\n⚠️ 残りのタスク取得に失敗しました```",
+	"done.notice":            "✅ <@%s> さんが割り当てたタスクを完了しました\n```%s```",
+	"done.header":            "✅ タスク完了！お疲れ様です！\n",
+	"done.remaining":         "\n📝 残りのタスク:\n",
+	"done.all_clear":         "\n🎉 もう残ってるタスクはありません！今日もよく頑張った！",
+	"delete.success":         "```⭕️ タスク削除しました```",
+	"edit.failed":            "```❌ タスクの編集に失敗しました: %s```",
+	"edit.success":           "```✅ 指定されたToDoを編集しました```",
+	"reset.failed":           "```❌ 今日のリセット失敗: %s```",
+	"reset.success":          "```✅ 今日のタスクを %d 件削除しました```",
+	"reset_all.confirm":      "```⚠️ 本当に全タスク（過去含む）を削除しますか？\n削除するには '%sconfirm reset' と入力してください。（10分以内）```",
+	"reset_all.expired":      "```⚠️ '%sreset all' の確認時間が切れました。再度実行してください。```",
+	"reset_all.failed":       "```❌ 全削除に失敗しました: %s```",
+	"reset_all.success":      "```✅ 全タスクを %d 件削除しました```",
+	"assigned.empty":         "```📭 割り当てたタスクはありません```",
+	"assigned.header":        "割り当てたタスクです！\n",
+	"chat.disabled":          "```⚠️ このサーバーでは !chat は無効になっています```",
+	"chat.title":             "🤖 AIコーチ",
+	"search.failed":          "```❌ 検索に失敗しました```",
+	"search.empty":           "```🔍 「%s」に一致するタスクはありません```",
+	"search.header":          "🔍 「%s」の検索結果（%d 件）\n",
+	"search.limited":         "上位 %d 件を表示しています\n",
+	"search.dates":           "    作成 %s\n",
+	"search.dates_completed": "    作成 %s / 完了 %s\n",
+	"note.added":             "```📝 #%d %s にメモを追加しました```",
+	"defer.success":          "```⏭ %d 件を %s に後回しにしました\n",
+	"list.age":               "（%d日目）",
+	"snooze.success":         "```😴 #%d %s を %s まで非表示にしました```",
+	"snooze.cleared":         "```⏰ #%d %s のスヌーズを解除しました```",
+	"snooze.woke":            "⏰ スヌーズしていたタスクが一覧に戻りました\n```#%d %s```",
+	"remind.success":         "```⏰ %s に #%d %s をリマインドします```",
+	"remind.cancelled":       "```🔕 #%d %s のリマインドを取り消しました```",
+	"remind.due":             "⏰ リマインドの時間です\n```#%d %s```",
+	"focus.started":          "```🍅 #%d %s に %d 分集中します（%s まで）。終わったらDMでお知らせします```",
+	"focus.stopped":          "```⏹ #%d %s の集中を止めました（%s）```",
+	"focus.ended":            "🍅 #%d %s の集中（%d分）が終わりました！おつかれさまです\n休憩するなら `!break %d` でタイマーを始められます",
+	"break.started":          "```☕ %d 分休憩します（%s まで）```",
+	"break.stopped":          "```⏹ 休憩タイマーを止めました```",
+	"break.ended":            "☕ 休憩（%d分）が終わりました。次は `!focus <ID> %d` でどうぞ",
+	"list.focus_today":       "\n🍅 今日の集中: %s\n",
+	"show.focus":             "集中: 合計 %s\n",
+	"show.estimate":          "見積もり: %s\n",
+	"track.started":          "```⏱ #%d %s の計測を始めました。止めるには !track stop```",
+	"track.switched":         "⏹ #%d %s の計測を止めました（%s）\n",
+	"track.stopped":          "```⏹ #%d %s の計測を止めました（%s）```",
+	"track.added":            "```📝 #%d %s に %s を記録しました```",
+	"timesheet.today":        "今日（%s）",
+	"timesheet.week":         "今週（%s〜%s）",
+	"timesheet.header":       "⏱ %sの記録: 合計 %s\n",
+	"timesheet.empty":        "⏱ %sの記録はまだありません\n",
+	"timesheet.by_day":       "\n▼日別\n",
+	"timesheet.by_task":      "\n▼タスク別\n",
+	"timesheet.by_tag":       "\n▼タグ別\n",
+	"timesheet.no_tag":       "（タグなし）",
+	"timesheet.running":      "\n⏺ 計測中: #%d %s（%s から）\n",
+	"timesheet.csv":          "📎 記録 %d 件をCSVにしました",
+	"estimates.header":       "📐 見積もりと実績（%s 以降に完了したタスク）\n",
+	"estimates.empty":        "比べられるタスクはまだありません。`!add 資料作成 P2 est:2h` のように見積もりを付けて、`!track` で時間を記録してみましょう\n",
+	"estimates.priority":     "%s P%d",
+	"estimates.total":        "合計",
+	"estimates.row":          "%s: %d件 見積もり %s → 実績 %s（%d%%）\n",
+	"estimates.unknown":      "※ 実績が分からないタスク %d 件は除いています（`!track`・`!focus` で記録するか、`!start` してから完了すると実績になります）\n",
+	"hours.current":          "```⏰ 1日に使える時間: %s```",
+	"hours.updated":          "```✅ 1日に使える時間を %s にしました```",
+	"habit.header":           "🔁 **習慣**\n",
+	"habit.line":             "%s %s — 🔥連続 %d 日（最長 %d 日）\n",
+	"habit.empty":            "習慣はまだありません。`!habit add 筋トレ` のように追加してください",
+	"habit.added":            "```🔁 習慣「%s」を追加しました。やった日は !habit check で記録してください```",
+	"habit.checked":          "```✅ %s を記録しました！🔥連続 %d 日```",
+	"habit.record":           "🏆 自己ベストです！",
+	"habit.deleted":          "```🗑 習慣「%s」を削除しました```",
+	"habit.heatmap_header":   "📅 **%s**（直近%d週）\n",
+	"habit.weekdays":         "月,火,水,木,金,土,日",
+	"duration.minutes":       "%d分",
+	"duration.hours_minutes": "%d時間%d分",
+	"reopen.success":         "```↩️ #%d %s を未着手に戻しました```",
+	"show.status":            "状態: %s / 優先度: %s P%d\n",
+	"show.list_personal":     "リスト: 個人\n",
+	"show.list_shared":       "リスト: <#%s> の共有リスト\n",
+	"show.created":           "作成: %s（👤 %s）\n",
+	"show.assignee":          "担当: %s\n",
+	"show.completed":         "完了: %s（✅ %s）\n",
+	"show.cancelled":         "キャンセル: %s（🚫 %s）\n",
+	"show.snoozed":           "スヌーズ: %s まで\n",
+	"show.reminders":         "リマインド: %s\n",
+	"show.reason":            "理由: %s\n",
+	"show.notes":             "\n📝 **メモ**\n",
+	"show.no_notes":          "\n📝 メモはまだありません（`%snote %d <メモ>` で追加できます）\n",
+	"show.history":           "\n📜 **状態の履歴**\n",
+	"status.todo":            "📝 未着手",
+	"status.in_progress":     "🏃 進行中",
+	"status.blocked":         "🚧 ブロック中",
+	"status.waiting":         "⏳ 待ち",
+	"status.done":            "✅ 完了",
+	"status.cancelled":       "🚫 キャンセル",
+	"status.changed":         "```%s に変更しました: %s```",
+	"status.changed_reason":  "```%s に変更しました: %s\n理由: %s```",
+	"status.reason_required": "```⚠️ ブロックの理由を入力してください```",
+
+	// 共有リスト・ダイジェスト
+	"team.on":           "```✅ このチャンネルを共有リストモードにしました。%[1]sadd / %[1]slist などはチャンネルの共有リストに対して動きます```",
+	"team.off":          "```✅ 共有リストモードを解除しました（共有タスクは %slist @team で確認できます）```",
+	"digest.joined":     "```✅ デイリーダイジェストに参加しました。毎朝、昨日の完了タスクと今日のP1タスクが共有されます```",
+	"digest.no_channel": "\n⚠️ 投稿先チャンネルが未設定です（管理者が `%sconfig digest #チャンネル` で設定できます）",
+	"digest.left":       "```✅ デイリーダイジェストから抜けました```",
+	"digest.header":     "☀️ **おはようございます！今日のチームダイジェストです**\n",
+	"digest.member":     "\n👤 <@%s>\n",
+	"digest.done":       "✅ 昨日の完了: %s\n",
+	"digest.p1":         "🔴 今日のP1: %s\n",
+	"digest.none":       "なし",
+	"digest.separator":  "、",
+
+	// 設定
+	"config.guild_only":     "```⚠️ 設定はサーバーのチャンネルでのみ使えます```",
+	"config.unset":          "（未設定）",
+	"config.show":           "⚙️ 現在の設定\n接頭辞: `%s`\nダイジェスト投稿先: %s（応援メッセージ: `%s`）\n返信先: `%s`\n言語: `%s`\n!chat: `%s`",
+	"config.digest_invalid": "```⚠️ チャンネルをメンション（#チャンネル）するか、here / off を指定してください```",
+	"config.updated":        "```✅ 設定を更新しました```",
+	"lang.current":          "```🌐 現在の言語: %s（利用可能: %s）```",
+	"lang.updated":          "```✅ 言語を %s に設定しました```",
+	"lang.reset":            "```✅ 言語設定を解除しました（サーバーやDiscordの設定に従います）```",
+
+	// Bot管理
+	"broadcast.fetch_failed": "```❌ 送信先の取得に失敗しました```",
+	"broadcast.header":       "📢 お知らせ\n",
+	"broadcast.sent":         "```📢 %d / %d 人に送信しました```",
+	"stats.failed":           "```❌ 集計に失敗しました```",
+	"stats.empty":            "```📭 まだユーザがいません```",
+	"stats.header":           "📊 全ユーザの集計（%d 人）\n",
+	"stats.columns":          "ユーザ | 未完了 | 完了 | 今日完了\n",
+	"stats.more":             "…ほか %d 人\n",
+	"help.title":             "**📋 Self-Management Bot コマンド一覧**\n以下のコマンドを使って、タスクの管理やAIとの対話ができます！\n\n",
+
+	// サービス層のエラー
+	"err.fetch_tasks":          "タスク取得に失敗",
+	"err.no_tasks":             "タスクが1件も登録されていません",
+	"err.no_such_task":         "指定されたタスク番号は存在しません",
+	"err.shared_edit":          "共有タスクを編集できるのは作成者・担当者・管理者のみです",
+	"err.shared_delete":        "共有タスクを削除できるのは作成者・担当者・管理者のみです",
+	"err.shared_cancel":        "共有タスクをキャンセルできるのは作成者・担当者・管理者のみです",
+	"err.already_in_status":    "タスクはすでに「%s」です",
+	"err.invalid_transition":   "「%s」のタスクは「%s」にできません",
+	"err.shared_guild_only":    "共有リストはサーバーのチャンネルでのみ使えます",
+	"err.channel_list_fetch":   "チャンネル設定の取得に失敗",
+	"err.settings_guild_only":  "設定はサーバーのチャンネルでのみ変更できます",
+	"err.unknown_setting":      "不明な設定項目です: %s",
+	"err.setting_not_channel":  "%s はチャンネル単位では設定できません",
+	"err.prefix_invalid":       "接頭辞は空白を含まない3文字以内で指定してください",
+	"err.reply_invalid":        "reply は channel か dm を指定してください",
+	"err.lang_invalid":         "言語は %s のいずれかを指定してください",
+	"err.onoff_invalid":        "%s は on か off を指定してください",
+	"err.settings_save":        "設定の保存に失敗",
+	"err.digest_guild_only":    "ダイジェストはサーバーのチャンネルでのみ使えます",
+	"err.chat_pending_fetch":   "ユーザーのタスク取得に失敗しました(Pending)",
+	"err.chat_notes_fetch":     "タスクのメモの取得に失敗しました",
+	"err.chat_completed_fetch": "ユーザーのタスク取得に失敗しました(Completed)",
+	"err.chat_llm":             "応答に失敗しました(LLM)",
+	"err.task_not_found":       "タスク #%d は見つかりません",
+	"err.not_closed":           "タスク #%d は完了・キャンセルしていません",
+	"err.status_changed":       "タスクの状態が他の操作で変わりました。もう一度確認してください",
+	"err.status_save":          "状態の変更に失敗",
+	"err.note_save":            "メモの保存に失敗",
+	"err.invalid_future_date":  "日付は tomorrow（明日）・3d（3日後）・2w（2週間後）・YYYY-MM-DD のいずれかで指定してください: %s",
+	"err.date_not_future":      "日付は明日以降を指定してください: %s",
+	"err.defer_closed":         "タスク #%d はもう終わっています",
+	"err.not_snoozed":          "タスク #%d はスヌーズしていません",
+	"err.snooze_save":          "スヌーズの保存に失敗",
+	"err.invalid_remind_time":  "時刻は 15:30 か in 2h（2時間後）・in 30m（30分後）の形式で指定してください: %s",
+	"err.remind_save":          "リマインドの保存に失敗",
+	"err.no_reminders":         "タスク #%d に取り消せるリマインドはありません",
+	"err.focus_minutes":        "分数は 1〜%d で指定してください",
+	"err.focus_running":        "タイマーが動いています。止めるには !focus stop",
+	"err.focus_not_running":    "動いているタイマーはありません",
+	"err.focus_save":           "タイマーの保存に失敗",
+	"err.invalid_duration":     "時間は 45m、1h30m、90（分）のように指定してください: %s",
+	"err.track_minutes":        "時間は 1分〜%d時間 で指定してください",
+	"err.track_already":        "タスク #%d はもう計測中です",
+	"err.track_not_running":    "計測中のタスクはありません",
+	"err.track_save":           "時間の記録に失敗",
+	"err.timesheet_arg":        "week か csv を指定してください: %s",
+	"err.hours_range":          "時間は 24 以下の正の数で指定してください",
+	"err.habit_name":           "習慣の名前は1〜%d文字で指定してください",
+	"err.habit_exists":         "習慣「%s」はもうあります",
+	"err.habit_not_found":      "習慣「%s」はありません（!habit で一覧を確認できます）",
+	"err.habit_checked":        "今日の「%s」はもう記録済みです",
+	"err.habit_save":           "習慣の保存に失敗",
+	"err.habit_fetch":          "習慣の取得に失敗",
+	"err.defer_save":           "後回しの保存に失敗",
+	"err.invalid_date":         "日付は YYYY-MM-DD の形式で指定してください: %s",
+
+	// LLMへのプロンプト
+	"prompt.answer_language":         "回答は日本語で書いてください。\n",
+	"prompt.chat.role":               "あなたは，自己管理を支援するメンズコーチです．\n\n",
+	"prompt.chat.pending":            "【未完了のタスク】\n",
+	"prompt.chat.pending_none":       "（未完了のタスクはありません）\n",
+	"prompt.chat.completed":          "\n【最近完了したタスク】\n",
+	"prompt.chat.completed_none":     "（完了したタスクはありません）\n",
+	"prompt.chat.note":               "  メモ: %s\n",
+	"prompt.chat.question":           "\n【ユーザーの質問】\n",
+	"prompt.chat.instruction":        "\n上記を踏まえてアドバイスせよ．",
+	"prompt.reminder.role":           "あなたは自己管理を支援するプロフェッショナルなコーチです。\n",
+	"prompt.reminder.goal":           "昨日のタスクの実行状況をふまえ、今日を気持ちよくスタートできるように前向きで実用的なアドバイスを与えてください。\n",
+	"prompt.reminder.rules":          "以下のルールに従ってください：\n- 昨日の達成を簡潔に肯定的に振り返る（完了したタスクがあれば）\n- 昨日未完了だったタスクがあれば、それをどう今日活かすか助言する\n- アドバイスは1〜3個、シンプルかつ実行可能なものにする\n\n",
+	"prompt.reminder.status":         "【昨日のタスク状況】\n",
+	"prompt.reminder.completed":      "▼完了したタスク：\n",
+	"prompt.reminder.pending":        "▼未完了のタスク：\n",
+	"prompt.reminder.none_done":      "▼完了したタスク：\n（完了したタスクはありません）\n",
+	"prompt.reminder.none_pending":   "▼未完了のタスク：\n（未完了のタスクはありません）\n",
+	"prompt.reminder.chronic":        "▼何日も持ち越しているタスク：\n",
+	"prompt.reminder.chronic_item":   "- %s（%d日目、後回し %d 回）\n",
+	"prompt.reminder.chronic_advice": "※ 持ち越しが続いているタスクには、責めずに、小さく分ける・今日の最初にやる・やめると決めるなど具体的な一歩を1つ提案してください\n",
+	"prompt.reminder.focus":          "▼昨日の集中時間：%d分\n",
+	"prompt.reminder.estimate":       "▼今日の未完了タスクの見積もり合計：%d分（1日に使える時間：%d分、見積もりのないタスク：%d件）\n",
+	"prompt.reminder.overcommit":     "※ 見積もりの合計が使える時間を %d 分超えています。今日やるタスクを絞る・後回しにする（!defer）よう、はっきり注意してください\n",
+	"prompt.reminder.habits":         "▼習慣（昨日やったか、連続日数）：\n",
+	"prompt.reminder.habit_item":     "- %s：%s（連続%d日）\n",
+	"prompt.reminder.habit_done":     "やった",
+	"prompt.reminder.habit_missed":   "やっていない",
+	"prompt.reminder.instruction":    "\nこの情報をふまえて、今日をポジティブに始めるためのメッセージを作成してください。\n",
+	"prompt.peptalk.role":            "あなたはチームを励ますコーチです。\n",
+	"prompt.peptalk.goal":            "以下のチームの状況をふまえ、今日を前向きに始められる応援メッセージを2〜3文で書いてください。\n個人名は出さず、チーム全体に向けて書いてください。\n\n",
+	"prompt.peptalk.status":          "【チームの状況】\n",
+	"prompt.peptalk.member":          "- メンバー%d: 昨日の完了 %d 件 / 今日のP1 %d 件\n",
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// Init は出力形式（json/text）とレベルを指定してデフォルトロガーを設定します。
+func Init(format, level string) {
+	slog.SetDefault(slog.New(newHandler(os.Stdout, format, level)))
+}
+
+func newHandler(w io.Writer, format, level string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: parseLevel(level)}
+	if strings.ToLower(format) == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Fields はコマンド1回分の相関情報です。
+type Fields struct {
+	CorrelationID string
+	UserID        string
+	GuildID       string
+	ChannelID     string
+	Command       string
+}
+
+// NewContext は相関情報を付与したロガーを ctx に載せて返します。
+// CorrelationID が空なら新しく採番します。
+func NewContext(ctx context.Context, f Fields) context.Context {
+	if f.CorrelationID == "" {
+		f.CorrelationID = NewCorrelationID()
+	}
+	attrs := []any{slog.String("correlation_id", f.CorrelationID)}
+	if f.UserID != "" {
+		attrs = append(attrs, slog.String("user_id", f.UserID))
+	}
+	if f.GuildID != "" {
+		attrs = append(attrs, slog.String("guild_id", f.GuildID))
+	}
+	if f.ChannelID != "" {
+		attrs = append(attrs, slog.String("channel_id", f.ChannelID))
+	}
+	if f.Command != "" {
+		attrs = append(attrs, slog.String("command", f.Command))
+	}
+	return context.WithValue(ctx, ctxKey{}, From(ctx).With(attrs...))
+}
+
+// From は ctx に載っているロガーを返します。無ければデフォルトロガーです。
+func From(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
+
+// NewCorrelationID はログ追跡用のランダムなIDを返します。
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"self-management-bot/db"
+)
+
+// AddDigestMember サーバーのデイリーダイジェストに参加する
+func AddDigestMember(ctx context.Context, guildID, userID string) error {
+	query := `INSERT INTO digest_members (guild_id, user_id) VALUES ($1, $2)
+		ON CONFLICT (guild_id, user_id) DO NOTHING`
+	_, err := db.DB.ExecContext(ctx, query, guildID, userID)
+	return err
+}
+
+// RemoveDigestMember サーバーのデイリーダイジェストから抜ける
+func RemoveDigestMember(ctx context.Context, guildID, userID string) error {
+	query := `DELETE FROM digest_members WHERE guild_id = $1 AND user_id = $2`
+	_, err := db.DB.ExecContext(ctx, query, guildID, userID)
+	return err
+}
+
+// FindDigestMembers サーバーのダイジェスト参加者を取得する
+func FindDigestMembers(ctx context.Context, guildID string) ([]string, error) {
+	query := `SELECT user_id FROM digest_members WHERE guild_id = $1 ORDER BY created_at`
+	var userIDs []string
+	err := db.DB.SelectContext(ctx, &userIDs, query, guildID)
+	return userIDs, err
+}
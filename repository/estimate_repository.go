@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// EstimateResult は見積もりのある完了したタスクと、実績を求めるための記録です。
+// StartedAt は最初に進行中にした日時、CompletedAt は完了した日時（'YYYY-MM-DD HH:MM'）です。
+type EstimateResult struct {
+	ID              int            `db:"id"`
+	PriorityID      int            `db:"priority_id"`
+	EstimateMinutes int            `db:"estimate_minutes"`
+	TrackedMinutes  int            `db:"tracked_minutes"`
+	FocusMinutes    int            `db:"focus_minutes"`
+	StartedAt       sql.NullString `db:"started_at"`
+	CompletedAt     sql.NullString `db:"completed_at"`
+}
+
+// FindEstimateResults userID が since（'YYYY-MM-DD'）以降に完了した、見積もりのあるタスクを取得する
+func FindEstimateResults(ctx context.Context, userID, since string) ([]EstimateResult, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.priority_id, t.estimate_minutes,
+			COALESCE((SELECT SUM(e.minutes) FROM time_entries e WHERE e.task_id = t.id AND e.ended_at IS NOT NULL), 0) AS tracked_minutes,
+			COALESCE((SELECT SUM(f.minutes) FROM focus_sessions f WHERE f.task_id = t.id AND f.kind = 'focus' AND f.ended_at IS NOT NULL), 0) AS focus_minutes,
+			(SELECT %s FROM task_events ev WHERE ev.task_id = t.id AND ev.to_status = 'in_progress') AS started_at,
+			%s AS completed_at
+		FROM tasks t
+		WHERE t.completed_by = $1 AND t.status = 'done' AND t.estimate_minutes IS NOT NULL AND %s >= $2
+		ORDER BY t.priority_id, t.id`,
+		db.DateTimeText("MIN(ev.created_at)"), db.DateTimeText("t.completed_at"), db.DateText("t.completed_at"))
+	var results []EstimateResult
+	err := db.DB.SelectContext(ctx, &results, query, userID, since)
+	return results, err
+}
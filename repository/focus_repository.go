@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"self-management-bot/db"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// タイマーの種類です。
+const (
+	FocusKindFocus = "focus" // 集中
+	FocusKindBreak = "break" // 休憩
+)
+
+// FocusSession は集中・休憩のタイマー1回分です。StartedAt / EndsAt は 'YYYY-MM-DD HH:MM' 形式です。
+type FocusSession struct {
+	ID        int           `db:"id"`
+	TaskID    sql.NullInt64 `db:"task_id"`
+	UserID    string        `db:"user_id"`
+	Kind      string        `db:"kind"`
+	Minutes   int           `db:"minutes"`
+	StartedAt string        `db:"started_at"`
+	EndsAt    string        `db:"ends_at"`
+	// Title は集中したタスクのタイトルです（休憩では空）。
+	Title string `db:"title"`
+	// Attempts は今回を含めた終了の通知の試行回数です（ClaimEndedFocusSessions のみ）。
+	Attempts int `db:"attempts"`
+}
+
+// StartFocusSession タイマーを始める。taskID が 0 なら休憩。startedAt / endsAt は db.TimeValue で変換した時刻
+func StartFocusSession(ctx context.Context, userID string, taskID int, kind string, minutes int, startedAt, endsAt string) error {
+	task := sql.NullInt64{Int64: int64(taskID), Valid: taskID != 0}
+	query := `INSERT INTO focus_sessions (task_id, user_id, kind, minutes, started_at, ends_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := db.DB.ExecContext(ctx, query, task, userID, kind, minutes, startedAt, endsAt)
+	return err
+}
+
+// focusColumns は FocusSession を取得する列です。
+func focusColumns() string {
+	return fmt.Sprintf(`f.id, f.task_id, f.user_id, f.kind, f.minutes, %s AS started_at, %s AS ends_at, COALESCE(t.title, '') AS title`,
+		db.DateTimeText("f.started_at"), db.DateTimeText("f.ends_at"))
+}
+
+// FindRunningFocusSession userID の実行中のタイマーを取得する。なければ sql.ErrNoRows
+func FindRunningFocusSession(ctx context.Context, userID string) (FocusSession, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM focus_sessions f LEFT JOIN tasks t ON t.id = f.task_id
+		WHERE f.user_id = $1 AND f.ended_at IS NULL
+		ORDER BY f.id DESC LIMIT 1`, focusColumns())
+	var session FocusSession
+	err := db.DB.GetContext(ctx, &session, query, userID)
+	return session, err
+}
+
+// StopFocusSession 実行中のタイマーを止め、実際の分数を記録する。止めたタイマーの終了は通知しない
+func StopFocusSession(ctx context.Context, sessionID, minutes int, endedAt string) error {
+	query := `UPDATE focus_sessions SET ended_at = $3, notified_at = $3, minutes = $2 WHERE id = $1 AND ended_at IS NULL`
+	_, err := db.DB.ExecContext(ctx, query, sessionID, minutes, endedAt)
+	return err
+}
+
+// ClaimEndedFocusSessions now（db.TimeValue）までに終わったタイマーを終了済みにし、終了の通知を lockedUntil まで取って返す
+// 取ったタイマーは、通知できたら MarkFocusNotified、失敗したら RetryFocusNotice か FailFocusNotice で記録する
+// ClaimDueReminders と同じく、通知中にプロセスが落ちたときは lockedUntil を過ぎてから他のプロセスが取り直す
+func ClaimEndedFocusSessions(ctx context.Context, now, lockedUntil string) ([]FocusSession, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, f.attempts FROM focus_sessions f LEFT JOIN tasks t ON t.id = f.task_id
+		WHERE f.notified_at IS NULL AND f.failed_at IS NULL AND f.ends_at <= $1
+			AND (f.locked_until IS NULL OR f.locked_until <= $1)
+		ORDER BY f.ends_at, f.id`, focusColumns())
+	var sessions []FocusSession
+	if err := db.DB.SelectContext(ctx, &sessions, query, now); err != nil {
+		return nil, err
+	}
+	claimed := sessions[:0]
+	for _, session := range sessions {
+		res, err := db.DB.ExecContext(ctx, `
+			UPDATE focus_sessions SET ended_at = COALESCE(ended_at, ends_at), locked_until = $3, attempts = attempts + 1
+			WHERE id = $1 AND notified_at IS NULL AND failed_at IS NULL AND (locked_until IS NULL OR locked_until <= $2)`,
+			session.ID, now, lockedUntil)
+		if err != nil {
+			return claimed, err
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			session.Attempts++
+			claimed = append(claimed, session)
+		}
+	}
+	return claimed, nil
+}
+
+// MarkFocusNotified タイマーの終了を通知済みにする。notifiedAt は db.TimeValue で変換した時刻
+func MarkFocusNotified(ctx context.Context, sessionID int, notifiedAt string) error {
+	_, err := db.DB.ExecContext(ctx, `UPDATE focus_sessions SET notified_at = $2, locked_until = NULL WHERE id = $1`, sessionID, notifiedAt)
+	return err
+}
+
+// RetryFocusNotice 通知に失敗したタイマーを retryAt（db.TimeValue）まで取らないようにする
+func RetryFocusNotice(ctx context.Context, sessionID int, retryAt string) error {
+	_, err := db.DB.ExecContext(ctx, `UPDATE focus_sessions SET locked_until = $2 WHERE id = $1`, sessionID, retryAt)
+	return err
+}
+
+// FailFocusNotice 再試行の上限に達した通知をあきらめたものとして記録する。failedAt は db.TimeValue で変換した時刻
+func FailFocusNotice(ctx context.Context, sessionID int, failedAt string) error {
+	_, err := db.DB.ExecContext(ctx, `UPDATE focus_sessions SET failed_at = $2, locked_until = NULL WHERE id = $1`, sessionID, failedAt)
+	return err
+}
+
+// FocusMinutesByTask タスクごとの終わった集中の合計分数（キー: タスクID）
+func FocusMinutesByTask(ctx context.Context, taskIDs []int) (map[int]int, error) {
+	totals := map[int]int{}
+	if len(taskIDs) == 0 {
+		return totals, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT task_id, SUM(minutes) AS minutes FROM focus_sessions
+		WHERE kind = 'focus' AND ended_at IS NOT NULL AND task_id IN (?)
+		GROUP BY task_id`, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		TaskID  int `db:"task_id"`
+		Minutes int `db:"minutes"`
+	}
+	if err := db.DB.SelectContext(ctx, &rows, db.DB.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		totals[r.TaskID] = r.Minutes
+	}
+	return totals, nil
+}
+
+// FocusMinutesOn userID が date（'YYYY-MM-DD'）に始めた終わった集中の合計分数
+func FocusMinutesOn(ctx context.Context, userID, date string) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COALESCE(SUM(minutes), 0) FROM focus_sessions
+		WHERE user_id = $1 AND kind = 'focus' AND ended_at IS NOT NULL AND %s = $2`, db.DateText("started_at"))
+	var minutes int
+	err := db.DB.GetContext(ctx, &minutes, query, userID, date)
+	return minutes, err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"self-management-bot/db"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Habit は毎日チェックする習慣です。
+type Habit struct {
+	ID     int    `db:"id"`
+	UserID string `db:"user_id"`
+	Name   string `db:"name"`
+}
+
+// AddHabit 習慣を追加する
+func AddHabit(ctx context.Context, userID, name string) error {
+	_, err := db.DB.ExecContext(ctx, `INSERT INTO habits (user_id, name) VALUES ($1, $2)`, userID, name)
+	return err
+}
+
+// FindHabitByName userID の習慣を名前（大文字・小文字を区別しない）で取得する。なければ sql.ErrNoRows
+func FindHabitByName(ctx context.Context, userID, name string) (Habit, error) {
+	query := `SELECT id, user_id, name FROM habits WHERE user_id = $1 AND LOWER(name) = LOWER($2)`
+	var habit Habit
+	err := db.DB.GetContext(ctx, &habit, query, userID, name)
+	return habit, err
+}
+
+// FindHabits userID の習慣を追加した順に取得する
+func FindHabits(ctx context.Context, userID string) ([]Habit, error) {
+	var habits []Habit
+	err := db.DB.SelectContext(ctx, &habits, `SELECT id, user_id, name FROM habits WHERE user_id = $1 ORDER BY id`, userID)
+	return habits, err
+}
+
+// DeleteHabit 習慣とチェックの記録を削除する
+func DeleteHabit(ctx context.Context, habitID int) error {
+	_, err := db.DB.ExecContext(ctx, `DELETE FROM habits WHERE id = $1`, habitID)
+	return err
+}
+
+// CheckInHabit 習慣を day（'YYYY-MM-DD'）にやったと記録する。すでに記録済みなら false
+func CheckInHabit(ctx context.Context, habitID int, day string) (bool, error) {
+	query := `INSERT INTO habit_checkins (habit_id, day) VALUES ($1, $2) ON CONFLICT (habit_id, day) DO NOTHING`
+	res, err := db.DB.ExecContext(ctx, query, habitID, day)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// FindCheckInDays 習慣ごとのチェックした日（'YYYY-MM-DD'）を古い順に取得する（キー: 習慣ID）
+func FindCheckInDays(ctx context.Context, habitIDs []int) (map[int][]string, error) {
+	days := map[int][]string{}
+	if len(habitIDs) == 0 {
+		return days, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT habit_id, CAST(day AS TEXT) AS day FROM habit_checkins
+		WHERE habit_id IN (?)
+		ORDER BY habit_id, day`, habitIDs)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		HabitID int    `db:"habit_id"`
+		Day     string `db:"day"`
+	}
+	if err := db.DB.SelectContext(ctx, &rows, db.DB.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		days[r.HabitID] = append(days[r.HabitID], r.Day)
+	}
+	return days, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// Job は定期実行するジョブの実行状態です。NextRunAt は 'YYYY-MM-DD HH:MM' 形式（ローカル時刻）です。
+type Job struct {
+	Name      string `db:"name"`
+	Schedule  string `db:"schedule"`
+	NextRunAt string `db:"next_run_at"`
+	Attempts  int    `db:"attempts"`
+}
+
+// UpsertJob ジョブを登録する。すでにあればスケジュールが変わったときだけ次回の実行時刻を nextRunAt にする
+// nextRunAt は db.TimeValue で変換した時刻
+func UpsertJob(ctx context.Context, name, schedule, nextRunAt string) error {
+	query := `
+		INSERT INTO jobs (name, schedule, next_run_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET schedule = excluded.schedule, next_run_at = excluded.next_run_at, attempts = 0
+		WHERE jobs.schedule <> excluded.schedule`
+	_, err := db.DB.ExecContext(ctx, query, name, schedule, nextRunAt)
+	return err
+}
+
+// ClaimDueJob 実行時刻を過ぎていて、どのプロセスも実行していないジョブを1つ取り、lockedUntil まで workerID のものにする
+// 取れるジョブがなければ ok=false を返す。Postgresでは SKIP LOCKED で、他のプロセスが取ろうとしている行を待たずに飛ばす
+func ClaimDueJob(ctx context.Context, workerID, now, lockedUntil string) (job Job, ok bool, err error) {
+	query := fmt.Sprintf(`
+		UPDATE jobs SET locked_by = $1, locked_until = $3, attempts = attempts + 1
+		WHERE name = (
+			SELECT name FROM jobs
+			WHERE next_run_at <= $2 AND (locked_until IS NULL OR locked_until < $2)
+			ORDER BY next_run_at
+			LIMIT 1%s
+		)
+		RETURNING name, schedule, %s AS next_run_at, attempts`, db.SkipLocked(), db.DateTimeText("next_run_at"))
+	rows, err := db.DB.QueryxContext(ctx, query, workerID, now, lockedUntil)
+	if err != nil {
+		return job, false, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return job, false, rows.Err()
+	}
+	err = rows.StructScan(&job)
+	return job, err == nil, err
+}
+
+// FinishJob 実行を終えたジョブのロックを外し、次回の実行時刻と試行回数を記録する
+// 再試行するときは試行回数を残し、成功したときやあきらめたときは0に戻す
+func FinishJob(ctx context.Context, name, workerID, nextRunAt, lastRunAt, lastError string, attempts int) error {
+	query := `
+		UPDATE jobs SET next_run_at = $3, last_run_at = $4, last_error = $5, attempts = $6, locked_by = NULL, locked_until = NULL
+		WHERE name = $1 AND locked_by = $2`
+	_, err := db.DB.ExecContext(ctx, query, name, workerID, nextRunAt, lastRunAt, lastError, attempts)
+	return err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"self-management-bot/db"
+)
+
+const (
+	ScopePersonal = "personal" // 個人のリスト
+	ScopeChannel  = "channel"  // チャンネルで共有するリスト
+)
+
+// Scope はタスクリストの範囲（誰のリストか）を表します。
+type Scope struct {
+	Kind      string
+	UserID    string // personal の持ち主
+	GuildID   string
+	ChannelID string // channel の対象チャンネル
+}
+
+func PersonalScope(userID string) Scope {
+	return Scope{Kind: ScopePersonal, UserID: userID}
+}
+
+func ChannelScope(guildID, channelID string) Scope {
+	return Scope{Kind: ScopeChannel, GuildID: guildID, ChannelID: channelID}
+}
+
+// IsShared は共有リストかどうかを返します。
+func (s Scope) IsShared() bool {
+	return s.Kind == ScopeChannel
+}
+
+// where はスコープで絞り込む条件式と引数を返します。n はプレースホルダの番号です。
+func (s Scope) where(n int) (string, string) {
+	if s.IsShared() {
+		return fmt.Sprintf("scope = 'channel' AND scope_id = $%d", n), s.ChannelID
+	}
+	return fmt.Sprintf("scope = 'personal' AND user_id = $%d", n), s.UserID
+}
+
+// EnableChannelList はチャンネルを共有リストモードにします。
+func EnableChannelList(ctx context.Context, guildID, channelID, userID string) error {
+	query := `INSERT INTO channel_lists (channel_id, guild_id, created_by) VALUES ($1, $2, $3)
+		ON CONFLICT (channel_id) DO NOTHING`
+	_, err := db.DB.ExecContext(ctx, query, channelID, guildID, userID)
+	return err
+}
+
+// DisableChannelList はチャンネルの共有リストモードを解除します（タスクは残ります）。
+func DisableChannelList(ctx context.Context, channelID string) error {
+	query := `DELETE FROM channel_lists WHERE channel_id = $1`
+	_, err := db.DB.ExecContext(ctx, query, channelID)
+	return err
+}
+
+// IsChannelList はチャンネルが共有リストモードかどうかを返します。
+func IsChannelList(ctx context.Context, channelID string) (bool, error) {
+	query := `SELECT COUNT(*) FROM channel_lists WHERE channel_id = $1`
+	var n int
+	err := db.DB.GetContext(ctx, &n, query, channelID)
+	return n > 0, err
+}
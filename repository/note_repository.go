@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"self-management-bot/db"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TaskNote はタスクのメモ1件です。CreatedAt は 'YYYY-MM-DD HH:MM' 形式です。
+type TaskNote struct {
+	ID        int    `db:"id"`
+	TaskID    int    `db:"task_id"`
+	UserID    string `db:"user_id"`
+	Body      string `db:"body"`
+	CreatedAt string `db:"created_at"`
+}
+
+// AddTaskNote タスクにメモを追記する
+func AddTaskNote(ctx context.Context, taskID int, userID, body string) error {
+	query := `INSERT INTO task_notes (task_id, user_id, body) VALUES ($1, $2, $3)`
+	_, err := db.DB.ExecContext(ctx, query, taskID, userID, body)
+	return err
+}
+
+// FindNotesByTask タスクのメモを書いた順に取得する
+func FindNotesByTask(ctx context.Context, taskID int) ([]TaskNote, error) {
+	notes, err := FindNotesByTasks(ctx, []int{taskID})
+	return notes[taskID], err
+}
+
+// FindNotesByTasks 複数タスクのメモをまとめて取得する（キー: タスクID）
+func FindNotesByTasks(ctx context.Context, taskIDs []int) (map[int][]TaskNote, error) {
+	notes := map[int][]TaskNote{}
+	if len(taskIDs) == 0 {
+		return notes, nil
+	}
+	query, args, err := sqlx.In(fmt.Sprintf(`
+		SELECT id, task_id, user_id, body, %s AS created_at FROM task_notes
+		WHERE task_id IN (?)
+		ORDER BY id`, db.DateTimeText("created_at")), taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	var rows []TaskNote
+	if err := db.DB.SelectContext(ctx, &rows, db.DB.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, n := range rows {
+		notes[n.TaskID] = append(notes[n.TaskID], n)
+	}
+	return notes, nil
+}
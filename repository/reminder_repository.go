@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// DueReminder は送信時刻になったリマインドと、その対象のタスクです。
+type DueReminder struct {
+	ID     int    `db:"id"`
+	TaskID int    `db:"task_id"`
+	UserID string `db:"user_id"`
+	Title  string `db:"title"`
+	Status string `db:"status"`
+	// Attempts は今回を含めた送信の試行回数です。
+	Attempts int `db:"attempts"`
+}
+
+// AddReminder タスクのリマインドを登録する。remindAt は db.TimeValue で変換した時刻
+func AddReminder(ctx context.Context, taskID int, userID, remindAt string) error {
+	query := `INSERT INTO reminders (task_id, user_id, remind_at) VALUES ($1, $2, $3)`
+	_, err := db.DB.ExecContext(ctx, query, taskID, userID, remindAt)
+	return err
+}
+
+// DeleteReminders userID がタスクに登録した未送信のリマインドを取り消し、取り消した件数を返す
+func DeleteReminders(ctx context.Context, taskID int, userID string) (int, error) {
+	query := `DELETE FROM reminders WHERE task_id = $1 AND user_id = $2 AND sent_at IS NULL AND failed_at IS NULL`
+	res, err := db.DB.ExecContext(ctx, query, taskID, userID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// ClaimDueReminders now（db.TimeValue）までに送るべき未送信のリマインドを lockedUntil まで取り、試行回数を数えて返す
+// 取ったリマインドは、送れたら MarkReminderSent、失敗したら RetryReminder か FailReminder で記録する
+// 送信中にプロセスが落ちたときは lockedUntil を過ぎてから他のプロセスが取り直す
+// 1件ずつ条件付きで更新するため、複数のプロセスから呼ばれても同じリマインドは1つのプロセスにしか返らない
+func ClaimDueReminders(ctx context.Context, now, lockedUntil string) ([]DueReminder, error) {
+	query := `
+		SELECT r.id, r.task_id, r.user_id, t.title, t.status, r.attempts FROM reminders r
+		JOIN tasks t ON t.id = r.task_id
+		WHERE r.sent_at IS NULL AND r.failed_at IS NULL AND r.remind_at <= $1
+			AND (r.locked_until IS NULL OR r.locked_until <= $1)
+		ORDER BY r.remind_at, r.id`
+	var due []DueReminder
+	if err := db.DB.SelectContext(ctx, &due, query, now); err != nil {
+		return nil, err
+	}
+	claimed := due[:0]
+	for _, r := range due {
+		res, err := db.DB.ExecContext(ctx, `
+			UPDATE reminders SET locked_until = $3, attempts = attempts + 1
+			WHERE id = $1 AND sent_at IS NULL AND failed_at IS NULL AND (locked_until IS NULL OR locked_until <= $2)`,
+			r.ID, now, lockedUntil)
+		if err != nil {
+			return claimed, err
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			r.Attempts++
+			claimed = append(claimed, r)
+		}
+	}
+	return claimed, nil
+}
+
+// MarkReminderSent 取ったリマインドを送信済みにする。sentAt は db.TimeValue で変換した時刻
+func MarkReminderSent(ctx context.Context, id int, sentAt string) error {
+	_, err := db.DB.ExecContext(ctx, `UPDATE reminders SET sent_at = $2, locked_until = NULL WHERE id = $1`, id, sentAt)
+	return err
+}
+
+// RetryReminder 送信に失敗したリマインドを retryAt（db.TimeValue）まで取らないようにする
+func RetryReminder(ctx context.Context, id int, retryAt string) error {
+	_, err := db.DB.ExecContext(ctx, `UPDATE reminders SET locked_until = $2 WHERE id = $1`, id, retryAt)
+	return err
+}
+
+// FailReminder 再試行の上限に達したリマインドをあきらめたものとして記録する。failedAt は db.TimeValue で変換した時刻
+func FailReminder(ctx context.Context, id int, failedAt string) error {
+	_, err := db.DB.ExecContext(ctx, `UPDATE reminders SET failed_at = $2, locked_until = NULL WHERE id = $1`, id, failedAt)
+	return err
+}
+
+// PendingReminder はまだ送っていないリマインドです。RemindAt は 'YYYY-MM-DD HH:MM' 形式です。
+type PendingReminder struct {
+	TaskID   int    `db:"task_id"`
+	RemindAt string `db:"remind_at"`
+}
+
+// FindPendingReminders userID がタスクに登録した未送信のリマインドを時刻順に取得する
+func FindPendingReminders(ctx context.Context, taskID int, userID string) ([]PendingReminder, error) {
+	query := fmt.Sprintf(`
+		SELECT task_id, %s AS remind_at FROM reminders
+		WHERE task_id = $1 AND user_id = $2 AND sent_at IS NULL AND failed_at IS NULL
+		ORDER BY remind_at`, db.DateTimeText("remind_at"))
+	var reminders []PendingReminder
+	err := db.DB.SelectContext(ctx, &reminders, query, taskID, userID)
+	return reminders, err
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"self-management-bot/db"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RolloverTasks 前日までに作られて終わっていないタスクの持ち越し日数を1日増やす
+// 同じ日に何度呼んでも1回しか数えず、!defer で今日以降に後回しにしたタスクとスヌーズ中のタスクは数えない。数えた件数を返す
+func RolloverTasks(ctx context.Context) (int, error) {
+	today := db.Today()
+	query := fmt.Sprintf(`
+		UPDATE tasks SET carry_over_days = carry_over_days + 1, rolled_over_on = %[1]s
+		WHERE %[2]s AND %[3]s < %[1]s
+			AND (rolled_over_on IS NULL OR rolled_over_on < %[1]s)
+			AND (deferred_until IS NULL OR deferred_until < %[1]s)`, today, pendingCondition(), db.DateOf("created_at"))
+	res, err := db.DB.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// DeferTasks タスクをまとめて until（'YYYY-MM-DD'）まで後回しにし、後回しにした回数を数える
+// 1つのUPDATEで更新するので、途中で失敗しても一部のタスクだけが後回しになることはない
+func DeferTasks(ctx context.Context, taskIDs []int, until string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`UPDATE tasks SET deferred_until = ?, defer_count = defer_count + 1 WHERE id IN (?)`, until, taskIDs)
+	if err != nil {
+		return err
+	}
+	_, err = db.DB.ExecContext(ctx, db.DB.Rebind(query), args...)
+	return err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"self-management-bot/db"
+	"strings"
+)
+
+// DatedTask は作成日・完了日付きのタスクです。日付は 'YYYY-MM-DD' 形式です。
+type DatedTask struct {
+	Task
+	CreatedOn   string         `db:"created_on"`
+	CompletedOn sql.NullString `db:"completed_on"`
+	// SnoozedUntil はスヌーズ中のタスクが再表示される日です（FindTaskByID のみ）。
+	SnoozedUntil sql.NullString `db:"snoozed_until"`
+}
+
+// SearchTasks scope のタスクをタイトルとメモから、状態・日付を問わず検索する。関連度の高い順、同じなら新しい順に limit 件まで返す
+// Postgresでは全文検索（単語単位）とトライグラム（部分一致・日本語。ILIKE でもトライグラムのインデックスを使う）の両方で探し、SQLiteでは部分一致のみで探す
+func SearchTasks(ctx context.Context, scope Scope, text string, limit int) ([]DatedTask, error) {
+	scopeCondition, scopeArg := scope.where(1)
+	pattern := "%" + escapeLike(strings.ToLower(text)) + "%"
+	columns := fmt.Sprintf(`id, user_id, title, status, status_reason, priority_id, scope, scope_id, created_by, completed_by, assignee_id,
+			%s AS created_on, %s AS completed_on`, db.DateText("created_at"), db.DateText("completed_at"))
+
+	var query string
+	var args []interface{}
+	if db.Driver == db.DriverSQLite {
+		query = fmt.Sprintf(`
+			SELECT %s FROM tasks
+			WHERE %s AND (
+				%s
+				OR EXISTS (SELECT 1 FROM task_notes n WHERE n.task_id = tasks.id AND %s)
+			)
+			ORDER BY created_at DESC, id DESC
+			LIMIT %d`, columns, scopeCondition, db.ILike("title", "$2"), db.ILike("n.body", "$2"), limit)
+		args = []interface{}{scopeArg, pattern}
+	} else {
+		query = fmt.Sprintf(`
+			SELECT %s FROM tasks
+			WHERE %s AND (
+				to_tsvector('simple', title) @@ plainto_tsquery('simple', $2)
+				OR %s
+				OR title %% $2
+				OR EXISTS (
+					SELECT 1 FROM task_notes n WHERE n.task_id = tasks.id AND (
+						to_tsvector('simple', n.body) @@ plainto_tsquery('simple', $2)
+						OR %s
+					)
+				)
+			)
+			ORDER BY
+				ts_rank(to_tsvector('simple', title), plainto_tsquery('simple', $2)) DESC,
+				similarity(title, $2) DESC,
+				created_at DESC, id DESC
+			LIMIT %d`, columns, scopeCondition, db.ILike("title", "$3"), db.ILike("n.body", "$3"), limit)
+		args = []interface{}{scopeArg, text, pattern}
+	}
+	var results []DatedTask
+	err := db.DB.SelectContext(ctx, &results, query, args...)
+	return results, err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// GuildSettings は guild_settings の1行です。NULLの項目は未設定を表します。
+type GuildSettings struct {
+	GuildID         string         `db:"guild_id"`
+	ChannelID       string         `db:"channel_id"`
+	Prefix          sql.NullString `db:"prefix"`
+	DigestChannelID sql.NullString `db:"digest_channel_id"`
+	ReplyMode       sql.NullString `db:"reply_mode"`
+	Language        sql.NullString `db:"language"`
+	ChatEnabled     sql.NullBool   `db:"chat_enabled"`
+	DigestPepTalk   sql.NullBool   `db:"digest_pep_talk"`
+}
+
+// settingColumns は UpsertGuildSetting で更新できるカラムです。
+var settingColumns = map[string]bool{
+	"prefix":            true,
+	"digest_channel_id": true,
+	"reply_mode":        true,
+	"language":          true,
+	"chat_enabled":      true,
+	"digest_pep_talk":   true,
+}
+
+// FindGuildSettings サーバー全体の設定と、指定チャンネルの上書き設定を取得する
+func FindGuildSettings(ctx context.Context, guildID, channelID string) ([]GuildSettings, error) {
+	query := `SELECT guild_id, channel_id, prefix, digest_channel_id, reply_mode, language, chat_enabled, digest_pep_talk
+		FROM guild_settings
+		WHERE guild_id = $1 AND (channel_id = '' OR channel_id = $2)`
+	var settings []GuildSettings
+	err := db.DB.SelectContext(ctx, &settings, query, guildID, channelID)
+	return settings, err
+}
+
+// UpsertGuildSetting 設定を1項目だけ更新する。channelID が空ならサーバー全体、value が nil なら未設定に戻す
+func UpsertGuildSetting(ctx context.Context, guildID, channelID, column string, value interface{}) error {
+	if !settingColumns[column] {
+		return fmt.Errorf("unknown setting column: %s", column)
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO guild_settings (guild_id, channel_id, %[1]s) VALUES ($1, $2, $3)
+		ON CONFLICT (guild_id, channel_id) DO UPDATE SET %[1]s = EXCLUDED.%[1]s, updated_at = %[2]s`, column, db.Now())
+	_, err := db.DB.ExecContext(ctx, query, guildID, channelID, value)
+	return err
+}
+
+// FindDigestGuilds ダイジェストの投稿先が設定されているサーバーの設定を取得する
+func FindDigestGuilds(ctx context.Context) ([]GuildSettings, error) {
+	query := `SELECT guild_id, channel_id, prefix, digest_channel_id, reply_mode, language, chat_enabled, digest_pep_talk
+		FROM guild_settings
+		WHERE channel_id = '' AND digest_channel_id IS NOT NULL AND digest_channel_id <> ''`
+	var settings []GuildSettings
+	err := db.DB.SelectContext(ctx, &settings, query)
+	return settings, err
+}
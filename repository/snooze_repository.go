@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// SnoozeTask タスクを until（'YYYY-MM-DD'）まで非表示にする。until が空ならスヌーズを解除する
+func SnoozeTask(ctx context.Context, taskID int, until string) error {
+	value := sql.NullString{String: until, Valid: until != ""}
+	_, err := db.DB.ExecContext(ctx, `UPDATE tasks SET snoozed_until = $2 WHERE id = $1`, taskID, value)
+	return err
+}
+
+// WakeSnoozedTasks スヌーズの期限が来た終わっていないタスクのスヌーズを解除し、解除したタスクを返す
+// 1件ずつ解除できたものだけを返すので、並行して呼ばれても同じタスクを2回返さない
+func WakeSnoozedTasks(ctx context.Context) ([]Task, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, title, status, priority_id, scope, scope_id, guild_id, created_by, assignee_id FROM tasks
+		WHERE snoozed_until IS NOT NULL AND snoozed_until <= %s AND %s
+		ORDER BY id`, db.Today(), openCondition)
+	var tasks []Task
+	if err := db.DB.SelectContext(ctx, &tasks, query); err != nil {
+		return nil, err
+	}
+	woken := tasks[:0]
+	for _, task := range tasks {
+		res, err := db.DB.ExecContext(ctx, `UPDATE tasks SET snoozed_until = NULL WHERE id = $1 AND snoozed_until IS NOT NULL`, task.ID)
+		if err != nil {
+			return woken, err
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			woken = append(woken, task)
+		}
+	}
+	return woken, nil
+}
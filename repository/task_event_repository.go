@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// ErrStatusChanged は状態を変えようとしたタスクが、その間に別の操作で変わっていたことを表します。
+var ErrStatusChanged = errors.New("task status changed concurrently")
+
+// TaskEvent はタスクの状態変更1件です。CreatedAt は 'YYYY-MM-DD HH:MM' 形式です。
+type TaskEvent struct {
+	ID         int    `db:"id"`
+	TaskID     int    `db:"task_id"`
+	UserID     string `db:"user_id"`
+	FromStatus string `db:"from_status"`
+	ToStatus   string `db:"to_status"`
+	Reason     string `db:"reason"`
+	CreatedAt  string `db:"created_at"`
+}
+
+// TransitionTask タスクの状態を from から to に変え、task_events に記録する
+// reason は blocked / waiting の理由（それ以外は空）。done / cancelled にした場合は userID と日時を終了として記録し、
+// それ以外に戻した場合は終了の記録を消す。タスクがすでに from でなければ ErrStatusChanged を返す
+func TransitionTask(ctx context.Context, taskID int, userID, from, to, reason string) error {
+	tx, err := db.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE tasks SET status = $3, status_reason = $4, completed_by = '', completed_at = NULL WHERE id = $1 AND status = $2`
+	args := []interface{}{taskID, from, to, reason}
+	if to == StatusDone || to == StatusCancelled {
+		query = fmt.Sprintf(`UPDATE tasks SET status = $3, status_reason = $4, completed_by = $5, completed_at = %s WHERE id = $1 AND status = $2`, db.Now())
+		args = append(args, userID)
+	}
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrStatusChanged
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO task_events (task_id, user_id, from_status, to_status, reason) VALUES ($1, $2, $3, $4, $5)`,
+		taskID, userID, from, to, reason)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindEventsByTask タスクの状態変更を古い順に取得する
+func FindEventsByTask(ctx context.Context, taskID int) ([]TaskEvent, error) {
+	query := fmt.Sprintf(`
+		SELECT id, task_id, user_id, from_status, to_status, reason, %s AS created_at FROM task_events
+		WHERE task_id = $1
+		ORDER BY id`, db.DateTimeText("created_at"))
+	var events []TaskEvent
+	err := db.DB.SelectContext(ctx, &events, query, taskID)
+	return events, err
+}
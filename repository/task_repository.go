@@ -1,16 +1,87 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"self-management-bot/db"
+	"self-management-bot/logging"
+	"strings"
 )
 
 type Task struct {
-	ID         int    `db:"id"`
-	UserID     string `db:"user_id"`
-	Title      string `db:"title"`
-	PriorityID int    `db:"priority_id"`
-	Status     string `db:"status"`
+	ID          int    `db:"id"`
+	UserID      string `db:"user_id"`
+	Title       string `db:"title"`
+	PriorityID  int    `db:"priority_id"`
+	Status      string `db:"status"`
+	Scope       string `db:"scope"`
+	ScopeID     string `db:"scope_id"`
+	GuildID     string `db:"guild_id"`
+	CreatedBy   string `db:"created_by"`
+	CompletedBy string `db:"completed_by"`
+	AssigneeID  string `db:"assignee_id"`
+	// StatusReason は blocked / waiting にした理由です。
+	StatusReason string `db:"status_reason"`
+	// CarryOverDays は終わらないまま日をまたいだ日数、DeferCount は !defer で後回しにした回数です。
+	CarryOverDays int `db:"carry_over_days"`
+	DeferCount    int `db:"defer_count"`
+	// EstimateMinutes は見積もりの分数です（0 なら見積もりなし）。
+	EstimateMinutes int `db:"estimate_minutes"`
+}
+
+// タスクの状態です。許される遷移はサービス層で検証します。
+const (
+	StatusTodo       = "todo"        // 未着手
+	StatusInProgress = "in_progress" // 進行中
+	StatusBlocked    = "blocked"     // ブロック中（理由付き）
+	StatusWaiting    = "waiting"     // 他の人や外部の返事待ち
+	StatusDone       = "done"        // 完了
+	StatusCancelled  = "cancelled"   // 中止
+)
+
+// Statuses は一覧で表示する順に並べた全ての状態です。
+var Statuses = []string{StatusInProgress, StatusTodo, StatusWaiting, StatusBlocked, StatusDone, StatusCancelled}
+
+// IsOpen はまだ終わっていない（done / cancelled 以外の）タスクかどうかを返します。
+func (t Task) IsOpen() bool {
+	return t.Status != StatusDone && t.Status != StatusCancelled
+}
+
+// openCondition は終わっていないタスクを表すSQL条件です。
+const openCondition = "status NOT IN ('done', 'cancelled')"
+
+// visibleCondition はスヌーズ中でない（一覧・リマインドに出す）タスクを表すSQL条件です。
+func visibleCondition() string {
+	return fmt.Sprintf("(snoozed_until IS NULL OR snoozed_until <= %s)", db.Today())
+}
+
+// pendingCondition は終わっておらず、スヌーズ中でもないタスクを表すSQL条件です。
+func pendingCondition() string {
+	return openCondition + " AND " + visibleCondition()
+}
+
+// statusOrder は Statuses の順に並べるためのSQL式です。
+const statusOrder = `CASE status
+				WHEN 'in_progress' THEN 0
+				WHEN 'todo' THEN 1
+				WHEN 'waiting' THEN 2
+				WHEN 'blocked' THEN 3
+				WHEN 'done' THEN 4
+				ELSE 5
+			END`
+
+// taskListOrder は !list などのリストの並び（未完了→完了、優先度の順）です。今日のリストの番号もこの順に振ります。
+const taskListOrder = statusOrder + `, priority_id ASC, id ASC`
+
+// closedOn は終わった日（完了・中止した日）を表すSQL式です。
+func closedOn() string {
+	return db.DateOf("COALESCE(completed_at, created_at)")
+}
+
+// IsAssigned は他のユーザから割り当てられたタスクかどうかを返します。
+func (t Task) IsAssigned() bool {
+	return t.AssigneeID != "" && t.AssigneeID != t.CreatedBy
 }
 
 type Priority struct {
@@ -19,43 +90,155 @@ type Priority struct {
 	Emoji string `db:"emoji"`
 }
 
-func AddTask(userID, title string, priorityID int) error {
-	query := `INSERT INTO tasks (user_id, title, priority_id,status) VALUES ($1, $2, $3,'pending')`
-	_, err := db.DB.Exec(query, userID, title, priorityID)
+// AddTask scope のリストにタスクを追加する。createdBy は追加したユーザ、assigneeID は担当者（なければ空）
+func AddTask(ctx context.Context, scope Scope, createdBy, assigneeID, title string, priorityID, estimateMinutes int) error {
+	owner := scope.UserID
+	if scope.IsShared() {
+		owner = createdBy
+	}
+	estimate := sql.NullInt64{Int64: int64(estimateMinutes), Valid: estimateMinutes > 0}
+	query := `INSERT INTO tasks (user_id, title, priority_id, status, scope, scope_id, guild_id, created_by, assignee_id, estimate_minutes)
+		VALUES ($1, $2, $3, 'todo', $4, $5, $6, $7, $8, $9)`
+	_, err := db.DB.ExecContext(ctx, query, owner, title, priorityID, scope.Kind, scope.ChannelID, scope.GuildID, createdBy, assigneeID, estimate)
 	if err != nil {
-		fmt.Println("❌ AddTask error:", err)
+		logging.From(ctx).Error("AddTask failed", "error", err)
 	}
 	return err
 }
 
-// FindTaskByUserID 完了状況問わずタスクを出力
-func FindTaskByUserID(userID string, when string) ([]Task, error) {
-	baseQuery := `
-		SELECT id, title, status, priority_id FROM tasks
-		WHERE user_id = $1 %s
-		ORDER BY
-			CASE status
-				WHEN 'pending' THEN 0
-				WHEN 'completed' THEN 1
-			END,
-			priority_id ASC`
-	// 日付に応じてSQL文を変えて絞り込む
-	var dateCondition string
-	if when == "today" {
-		// 未完了タスクは日付問わず表示する
-		dateCondition = "AND (status = 'pending' OR (status = 'completed' AND created_at::date = CURRENT_DATE))"
-	} else if when == "yesterday" {
-		dateCondition = "AND created_at >= CURRENT_DATE - INTERVAL '1 day' AND created_at < CURRENT_DATE"
+// !list などで絞り込む状態です。Status* を指定するとその状態だけに絞り込みます。
+const (
+	StatusFilterToday   = ""        // 終わっていないものすべてと、今日終わったもの（既定）
+	StatusFilterOpen    = "open"    // 終わっていないもののみ
+	StatusFilterAll     = "all"     // 状態を問わない
+	StatusFilterSnoozed = "snoozed" // スヌーズ中のもののみ（他の絞り込みではスヌーズ中のタスクは出さない）
+)
+
+// TaskFilter はタスクの絞り込み条件です。ゼロ値の項目は絞り込みに使いません。
+type TaskFilter struct {
+	Scope      Scope
+	Status     string // StatusFilter* か Status*
+	PriorityID int
+	Since      string // 作成日がこの日以降（YYYY-MM-DD）
+	Until      string // 作成日がこの日以前（YYYY-MM-DD）
+	Text       string // タイトルに含まれる文字列（大文字小文字を区別しない）
+	Limit      int    // 取得件数の上限（0なら無制限）
+	Offset     int
+}
+
+// where は絞り込みの条件式と引数を返します。
+func (f TaskFilter) where() (string, []interface{}) {
+	scopeCondition, scopeArg := f.Scope.where(1)
+	conditions := []string{scopeCondition}
+	args := []interface{}{scopeArg}
+	placeholder := func(arg interface{}) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	// スヌーズ中のタスクは snoozed を指定したときだけ表示する
+	if f.Status == StatusFilterSnoozed {
+		conditions = append(conditions, openCondition, "NOT "+visibleCondition())
 	} else {
-		dateCondition = ""
+		conditions = append(conditions, visibleCondition())
+	}
+	switch f.Status {
+	case StatusFilterToday:
+		// 終わっていないタスクは日付問わず表示する
+		conditions = append(conditions, fmt.Sprintf("(%s OR %s = %s)", openCondition, closedOn(), db.Today()))
+	case StatusFilterOpen:
+		conditions = append(conditions, openCondition)
+	case StatusFilterAll, StatusFilterSnoozed:
+	default:
+		conditions = append(conditions, "status = "+placeholder(f.Status))
+	}
+	if f.PriorityID != 0 {
+		conditions = append(conditions, "priority_id = "+placeholder(f.PriorityID))
+	}
+	if f.Since != "" {
+		conditions = append(conditions, fmt.Sprintf("%s >= %s", db.DateOf("created_at"), placeholder(f.Since)))
 	}
-	query := fmt.Sprintf(baseQuery, dateCondition)
+	if f.Until != "" {
+		conditions = append(conditions, fmt.Sprintf("%s <= %s", db.DateOf("created_at"), placeholder(f.Until)))
+	}
+	if f.Text != "" {
+		conditions = append(conditions, db.ILike("title", placeholder("%"+escapeLike(strings.ToLower(f.Text))+"%")))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// escapeLike は LIKE のワイルドカードをエスケープします。
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
 
+// FindTasks filter に一致するタスクを未完了→完了、優先度の順に取得する
+func FindTasks(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	condition, args := filter.where()
+	query := `
+		SELECT id, user_id, title, status, status_reason, priority_id, scope, scope_id, created_by, completed_by, assignee_id,
+			carry_over_days, defer_count, COALESCE(estimate_minutes, 0) AS estimate_minutes
+		FROM tasks
+		WHERE ` + condition + `
+		ORDER BY ` + taskListOrder
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
+	}
 	var tasks []Task
-	err := db.DB.Select(&tasks, query, userID)
+	err := db.DB.SelectContext(ctx, &tasks, query, args...)
 	return tasks, err
 }
-func UpdateTask(taskID int, title string, priorityID *int) error {
+
+// CountTasks filter に一致するタスクの件数（Limit/Offset は無視する）
+func CountTasks(ctx context.Context, filter TaskFilter) (int, error) {
+	condition, args := filter.where()
+	var count int
+	err := db.DB.GetContext(ctx, &count, `SELECT COUNT(*) FROM tasks WHERE `+condition, args...)
+	return count, err
+}
+
+// TaskNumbers ids のタスクの、scope の今日のリスト（GetTaskService の並び）での番号（0始まり）を取得する（キー: タスクID）
+// 今日のリストにないタスクは含まない
+func TaskNumbers(ctx context.Context, scope Scope, ids []int) (map[int]int, error) {
+	numbers := map[int]int{}
+	if len(ids) == 0 {
+		return numbers, nil
+	}
+	condition, args := TaskFilter{Scope: scope}.where()
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	query := fmt.Sprintf(`
+		SELECT id, number FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY %s) - 1 AS number FROM tasks WHERE %s
+		) numbered
+		WHERE id IN (%s)`, taskListOrder, condition, strings.Join(placeholders, ", "))
+	var rows []struct {
+		ID     int `db:"id"`
+		Number int `db:"number"`
+	}
+	if err := db.DB.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		numbers[r.ID] = r.Number
+	}
+	return numbers, nil
+}
+
+// FindTaskByID タスクIDで作成日・完了日付きのタスクを取得する
+func FindTaskByID(ctx context.Context, taskID int) (DatedTask, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, title, status, status_reason, priority_id, scope, scope_id, guild_id, created_by, completed_by, assignee_id,
+			carry_over_days, defer_count, COALESCE(estimate_minutes, 0) AS estimate_minutes, CAST(snoozed_until AS TEXT) AS snoozed_until, %s AS created_on, %s AS completed_on
+		FROM tasks WHERE id = $1`, db.DateText("created_at"), db.DateText("completed_at"))
+	var task DatedTask
+	err := db.DB.GetContext(ctx, &task, query, taskID)
+	return task, err
+}
+
+func UpdateTask(ctx context.Context, taskID int, title string, priorityID *int) error {
 	var query string
 	var args []interface{}
 	// 1 value
@@ -71,54 +254,76 @@ func UpdateTask(taskID int, title string, priorityID *int) error {
 			args = []interface{}{title, *priorityID, taskID}
 		}
 	}
-	_, err := db.DB.Exec(query, args...)
-	return err
-}
-func CompleteTask(taskID int) error {
-	query := `UPDATE tasks SET status = 'completed' WHERE id = $1`
-	_, err := db.DB.Exec(query, taskID)
+	_, err := db.DB.ExecContext(ctx, query, args...)
 	return err
 }
-func DeleteTask(taskID int) error {
+
+func DeleteTask(ctx context.Context, taskID int) error {
 	query := `DELETE FROM tasks WHERE id = $1`
-	_, err := db.DB.Exec(query, taskID)
+	_, err := db.DB.ExecContext(ctx, query, taskID)
 	return err
 }
 
+// FindAssignedByUser userID が他のユーザに割り当てたタスク（終わっていないものすべてと今日終わったもの）
+func FindAssignedByUser(ctx context.Context, userID string) ([]Task, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, title, status, status_reason, priority_id, scope, scope_id, created_by, completed_by, assignee_id FROM tasks
+		WHERE created_by = $1 AND assignee_id <> '' AND assignee_id <> $1
+			AND (%s OR %s = %s)
+		ORDER BY
+			`+statusOrder+`,
+			priority_id ASC`, pendingCondition(), closedOn(), db.Today())
+	var tasks []Task
+	err := db.DB.SelectContext(ctx, &tasks, query, userID)
+	return tasks, err
+}
+
 // FindCompletedTodayTaskByUser 今日の完了済みタスク
-func FindCompletedTodayTaskByUser(userID string) ([]Task, error) {
-	query := `SELECT id,title,status FROM tasks 
-                       WHERE user_id = $1 AND status = 'completed' AND created_at::date = CURRENT_DATE
-                       ORDER BY created_at `
+func FindCompletedTodayTaskByUser(ctx context.Context, userID string) ([]Task, error) {
+	query := fmt.Sprintf(`SELECT id,title,status FROM tasks 
+                       WHERE user_id = $1 AND scope = 'personal' AND status = 'done' AND %s = %s
+                       ORDER BY created_at `, closedOn(), db.Today())
+	var tasks []Task
+	err := db.DB.SelectContext(ctx, &tasks, query, userID)
+	return tasks, err
+}
+
+// FindCompletedYesterdayByUser userID が昨日完了したタスク（作成日は問わない。共有リストで完了したものも含む）
+// 完了者が記録されていない古いタスクは持ち主が完了したものとみなす
+func FindCompletedYesterdayByUser(ctx context.Context, userID string) ([]Task, error) {
+	query := fmt.Sprintf(`SELECT id,title,status FROM tasks
+		WHERE COALESCE(NULLIF(completed_by, ''), user_id) = $1 AND status = 'done' AND %s = %s
+		ORDER BY completed_at, id`, closedOn(), db.DaysAgo(1))
 	var tasks []Task
-	err := db.DB.Select(&tasks, query, userID)
+	err := db.DB.SelectContext(ctx, &tasks, query, userID)
 	return tasks, err
 }
 
-// FindPendingTaskByUser 待ちタスク
-func FindPendingTaskByUser(userID string) ([]Task, error) {
-	query := `SELECT id,title,status FROM tasks 
-                       WHERE user_id = $1 AND status = 'pending'
+// FindPendingTaskByUser 終わっていないタスク
+func FindPendingTaskByUser(ctx context.Context, userID string) ([]Task, error) {
+	query := `SELECT id,title,status,status_reason,carry_over_days,defer_count,COALESCE(estimate_minutes, 0) AS estimate_minutes FROM tasks 
+                       WHERE user_id = $1 AND scope = 'personal' AND ` + pendingCondition() + `
                        ORDER BY created_at `
 	var tasks []Task
-	err := db.DB.Select(&tasks, query, userID)
+	err := db.DB.SelectContext(ctx, &tasks, query, userID)
 	return tasks, err
 }
 
-// FindAllUser ユーザIDを全て探す
-func FindAllUser() ([]string, error) {
-	query := `SELECT DISTINCT user_id FROM tasks`
+// FindAllUser ユーザIDを全て探す（個人のタスクか習慣を持っているユーザ）
+func FindAllUser(ctx context.Context) ([]string, error) {
+	query := `SELECT user_id FROM tasks WHERE scope = 'personal' UNION SELECT user_id FROM habits ORDER BY user_id`
 	var userIDs []string
-	err := db.DB.Select(&userIDs, query)
+	err := db.DB.SelectContext(ctx, &userIDs, query)
 	return userIDs, err
 }
 
-func DeleteTodayTasks(userID string) (int, error) {
-	query := `
+func DeleteTodayTasks(ctx context.Context, scope Scope) (int, error) {
+	scopeCondition, scopeArg := scope.where(1)
+	query := fmt.Sprintf(`
 		DELETE FROM tasks
-		WHERE user_id = $1 AND created_at::date = CURRENT_DATE
-	`
-	res, err := db.DB.Exec(query, userID)
+		WHERE %s AND %s = %s
+	`, scopeCondition, db.DateOf("created_at"), db.Today())
+	res, err := db.DB.ExecContext(ctx, query, scopeArg)
 	if err != nil {
 		return 0, err
 	}
@@ -126,12 +331,46 @@ func DeleteTodayTasks(userID string) (int, error) {
 	return int(rows), nil
 }
 
-func DeleteAllTasksByUser(userID string) (int, error) {
-	query := `DELETE FROM tasks WHERE user_id = $1`
-	res, err := db.DB.Exec(query, userID)
+func DeleteAllTasksByUser(ctx context.Context, userID string) (int, error) {
+	query := `DELETE FROM tasks WHERE user_id = $1 AND scope = 'personal'`
+	res, err := db.DB.ExecContext(ctx, query, userID)
 	if err != nil {
 		return 0, err
 	}
 	rows, _ := res.RowsAffected()
 	return int(rows), nil
 }
+
+// UserStats ユーザごとのタスク集計
+type UserStats struct {
+	UserID         string `db:"user_id"`
+	Pending        int    `db:"pending"`
+	Completed      int    `db:"completed"`
+	CompletedToday int    `db:"completed_today"`
+}
+
+// FindUserStats 全ユーザの個人タスクを集計する
+func FindUserStats(ctx context.Context) ([]UserStats, error) {
+	query := fmt.Sprintf(`
+		SELECT user_id,
+			SUM(CASE WHEN %[1]s THEN 1 ELSE 0 END) AS pending,
+			SUM(CASE WHEN status = 'done' THEN 1 ELSE 0 END) AS completed,
+			SUM(CASE WHEN status = 'done' AND %[2]s = %[3]s THEN 1 ELSE 0 END) AS completed_today
+		FROM tasks
+		WHERE scope = 'personal'
+		GROUP BY user_id
+		ORDER BY COUNT(*) DESC`, openCondition, closedOn(), db.Today())
+	var stats []UserStats
+	err := db.DB.SelectContext(ctx, &stats, query)
+	return stats, err
+}
+
+// FindPendingTaskByPriority 指定した優先度の個人の未完了タスク
+func FindPendingTaskByPriority(ctx context.Context, userID string, priorityID int) ([]Task, error) {
+	query := `SELECT id, title, status, priority_id FROM tasks
+		WHERE user_id = $1 AND scope = 'personal' AND ` + pendingCondition() + ` AND priority_id = $2
+		ORDER BY created_at`
+	var tasks []Task
+	err := db.DB.SelectContext(ctx, &tasks, query, userID, priorityID)
+	return tasks, err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// ErrTimeEntryStopped は止めようとした計測中の記録が、その間に別の操作で止められていたことを表します。
+var ErrTimeEntryStopped = errors.New("time entry already stopped")
+
+// TimeEntry はタスクにかけた時間の記録1件です。StartedAt は 'YYYY-MM-DD HH:MM'、Day は開始日の 'YYYY-MM-DD' です。
+type TimeEntry struct {
+	ID        int    `db:"id"`
+	TaskID    int    `db:"task_id"`
+	UserID    string `db:"user_id"`
+	Minutes   int    `db:"minutes"`
+	StartedAt string `db:"started_at"`
+	Day       string `db:"day"`
+	Title     string `db:"title"`
+}
+
+// timeEntryColumns は TimeEntry を取得する列です。
+func timeEntryColumns() string {
+	return fmt.Sprintf(`e.id, e.task_id, e.user_id, e.minutes, %s AS started_at, %s AS day, t.title`,
+		db.DateTimeText("e.started_at"), db.DateText("e.started_at"))
+}
+
+// StartTimeEntry 計測を始める。startedAt は db.TimeValue で変換した時刻
+// 計測中の記録は1人1件までなので、すでに計測中ならエラーになる
+func StartTimeEntry(ctx context.Context, userID string, taskID int, startedAt string) error {
+	query := `INSERT INTO time_entries (task_id, user_id, started_at) VALUES ($1, $2, $3)`
+	_, err := db.DB.ExecContext(ctx, query, taskID, userID, startedAt)
+	return err
+}
+
+// FindRunningTimeEntry userID の計測中の記録を取得する。なければ sql.ErrNoRows
+func FindRunningTimeEntry(ctx context.Context, userID string) (TimeEntry, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM time_entries e JOIN tasks t ON t.id = e.task_id
+		WHERE e.user_id = $1 AND e.ended_at IS NULL`, timeEntryColumns())
+	var entry TimeEntry
+	err := db.DB.GetContext(ctx, &entry, query, userID)
+	return entry, err
+}
+
+// StopTimeEntry 計測中の記録を止め、分数を記録する
+func StopTimeEntry(ctx context.Context, entryID, minutes int, endedAt string) error {
+	query := `UPDATE time_entries SET ended_at = $3, minutes = $2 WHERE id = $1 AND ended_at IS NULL`
+	_, err := db.DB.ExecContext(ctx, query, entryID, minutes, endedAt)
+	return err
+}
+
+// SwitchTimeEntry 計測中の記録 entryID を止めて分数を記録し、同じトランザクションで taskID の計測を始める
+// now は db.TimeValue で変換した時刻。記録がすでに止められていれば ErrTimeEntryStopped を返し、何も変えない
+func SwitchTimeEntry(ctx context.Context, entryID, minutes int, userID string, taskID int, now string) error {
+	tx, err := db.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE time_entries SET ended_at = $3, minutes = $2 WHERE id = $1 AND ended_at IS NULL`,
+		entryID, minutes, now)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTimeEntryStopped
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO time_entries (task_id, user_id, started_at) VALUES ($1, $2, $3)`, taskID, userID, now)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddTimeEntry 終わった記録を手入力で追加する。startedAt / endedAt は db.TimeValue で変換した時刻
+func AddTimeEntry(ctx context.Context, userID string, taskID, minutes int, startedAt, endedAt string) error {
+	query := `INSERT INTO time_entries (task_id, user_id, minutes, started_at, ended_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := db.DB.ExecContext(ctx, query, taskID, userID, minutes, startedAt, endedAt)
+	return err
+}
+
+// FindTimeEntries userID が since〜until（'YYYY-MM-DD'、両端を含む）に始めた終わった記録を古い順に取得する
+func FindTimeEntries(ctx context.Context, userID, since, until string) ([]TimeEntry, error) {
+	day := db.DateText("e.started_at")
+	query := fmt.Sprintf(`
+		SELECT %s FROM time_entries e JOIN tasks t ON t.id = e.task_id
+		WHERE e.user_id = $1 AND e.ended_at IS NOT NULL AND %s >= $2 AND %s <= $3
+		ORDER BY e.started_at, e.id`, timeEntryColumns(), day, day)
+	var entries []TimeEntry
+	err := db.DB.SelectContext(ctx, &entries, query, userID, since, until)
+	return entries, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"self-management-bot/db"
+)
+
+// UserSettings は user_settings の1行です。NULLの項目は未設定を表します。
+type UserSettings struct {
+	UserID        string         `db:"user_id"`
+	Language      sql.NullString `db:"language"`
+	DiscordLocale sql.NullString `db:"discord_locale"`
+	// AvailableMinutes は !hours で設定した1日に使える分数です。
+	AvailableMinutes sql.NullInt64 `db:"available_minutes"`
+}
+
+// FindUserSettings ユーザの設定を取得する。行が無ければ全項目が未設定の値を返す
+func FindUserSettings(ctx context.Context, userID string) (UserSettings, error) {
+	query := `SELECT user_id, language, discord_locale, available_minutes FROM user_settings WHERE user_id = $1`
+	var settings UserSettings
+	err := db.DB.GetContext(ctx, &settings, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserSettings{UserID: userID}, nil
+	}
+	return settings, err
+}
+
+// UpsertUserLanguage ユーザが選んだ言語を保存する。language が nil なら未設定に戻す
+func UpsertUserLanguage(ctx context.Context, userID string, language interface{}) error {
+	query := fmt.Sprintf(`
+		INSERT INTO user_settings (user_id, language) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET language = EXCLUDED.language, updated_at = %s`, db.Now())
+	_, err := db.DB.ExecContext(ctx, query, userID, language)
+	return err
+}
+
+// UpsertDiscordLocale インタラクションで得たDiscordの言語を保存する
+func UpsertDiscordLocale(ctx context.Context, userID, locale string) error {
+	query := fmt.Sprintf(`
+		INSERT INTO user_settings (user_id, discord_locale) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET discord_locale = EXCLUDED.discord_locale, updated_at = %s`, db.Now())
+	_, err := db.DB.ExecContext(ctx, query, userID, locale)
+	return err
+}
+
+// UpsertAvailableMinutes ユーザが1日に使える分数を保存する。minutes が nil なら未設定に戻す
+func UpsertAvailableMinutes(ctx context.Context, userID string, minutes interface{}) error {
+	query := fmt.Sprintf(`
+		INSERT INTO user_settings (user_id, available_minutes) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET available_minutes = EXCLUDED.available_minutes, updated_at = %s`, db.Now())
+	_, err := db.DB.ExecContext(ctx, query, userID, minutes)
+	return err
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []string{
+		"* * * *",       // 項目が足りない
+		"* * * * * *",   // 項目が多い
+		"60 * * * *",    // 分の範囲外
+		"* 24 * * *",    // 時の範囲外
+		"* * 0 * *",     // 日の範囲外
+		"* * * 13 *",    // 月の範囲外
+		"* * * * 8",     // 曜日の範囲外
+		"*/0 * * * *",   // 間隔が0
+		"5-1 * * * *",   // 範囲が逆
+		"a * * * *",     // 数値でない
+		"1-x * * * *",   // 範囲の終わりが数値でない
+		"*/x * * * *",   // 間隔が数値でない
+		"1,,2 * * * *",  // 空の値
+		"0 0 1-31/ * *", // 間隔が空
+	}
+	for _, expr := range tests {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) = nil error, want error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(loc *time.Location, year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return at(time.UTC, year, month, day, hour, minute)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", at(tokyo, 2026, 10, 19, 10, 7), at(tokyo, 2026, 10, 19, 10, 8)},
+		{"seconds are truncated", "* * * * *", at(tokyo, 2026, 10, 19, 10, 7).Add(59 * time.Second), at(tokyo, 2026, 10, 19, 10, 8)},
+		{"step", "*/15 * * * *", at(tokyo, 2026, 10, 19, 10, 7), at(tokyo, 2026, 10, 19, 10, 15)},
+		{"step wraps to next hour", "*/15 * * * *", at(tokyo, 2026, 10, 19, 10, 45), at(tokyo, 2026, 10, 19, 11, 0)},
+		{"value with step starts there", "5/20 * * * *", at(tokyo, 2026, 10, 19, 10, 26), at(tokyo, 2026, 10, 19, 10, 45)},
+		{"range", "0 9-17 * * *", at(tokyo, 2026, 10, 19, 17, 0), at(tokyo, 2026, 10, 20, 9, 0)},
+		{"range with step", "0 9-17/2 * * *", at(tokyo, 2026, 10, 19, 10, 0), at(tokyo, 2026, 10, 19, 11, 0)},
+		{"list", "0 6,12,19 * * *", at(tokyo, 2026, 10, 19, 12, 0), at(tokyo, 2026, 10, 19, 19, 0)},
+		{"list wraps to next day", "0 6,12,19 * * *", at(tokyo, 2026, 10, 19, 19, 0), at(tokyo, 2026, 10, 20, 6, 0)},
+		{"day of month", "0 0 1 * *", at(tokyo, 2026, 10, 19, 0, 0), at(tokyo, 2026, 11, 1, 0, 0)},
+		{"day of month skips short months", "0 0 31 * *", at(tokyo, 2026, 10, 31, 0, 0), at(tokyo, 2026, 12, 31, 0, 0)},
+		{"month", "0 0 1 1 *", at(tokyo, 2026, 10, 19, 0, 0), at(tokyo, 2027, 1, 1, 0, 0)},
+		{"leap day", "0 0 29 2 *", at(tokyo, 2026, 10, 19, 0, 0), at(tokyo, 2028, 2, 29, 0, 0)},
+		// 2026-10-19 は月曜日
+		{"day of week", "0 0 * * 5", at(tokyo, 2026, 10, 19, 0, 0), at(tokyo, 2026, 10, 23, 0, 0)},
+		{"7 is Sunday", "0 0 * * 7", at(tokyo, 2026, 10, 19, 0, 0), at(tokyo, 2026, 10, 25, 0, 0)},
+		{"0 is Sunday", "0 0 * * 0", at(tokyo, 2026, 10, 19, 0, 0), at(tokyo, 2026, 10, 25, 0, 0)},
+		{"weekday range", "0 9 * * 1-5", at(tokyo, 2026, 10, 23, 9, 0), at(tokyo, 2026, 10, 26, 9, 0)},
+		// 日と曜日の両方を指定したときは、どちらかに一致すれば実行する
+		{"day of month or week: month day first", "0 0 13 * 5", at(tokyo, 2026, 10, 10, 0, 0), at(tokyo, 2026, 10, 13, 0, 0)},
+		{"day of month or week: weekday first", "0 0 13 * 5", at(tokyo, 2026, 10, 13, 0, 0), at(tokyo, 2026, 10, 16, 0, 0)},
+		// 曜日だけ "*" なら日だけで判定する
+		{"day of month with any weekday", "0 0 13 * *", at(tokyo, 2026, 10, 13, 0, 0), at(tokyo, 2026, 11, 13, 0, 0)},
+		// 夏時間の始まり（2026-03-08 02:00 EST → 03:00 EDT）。存在しない 2:30 はその日は実行しない
+		{"DST spring forward skips missing time", "30 2 * * *", at(newYork, 2026, 3, 7, 3, 0), at(newYork, 2026, 3, 9, 2, 30)},
+		{"DST spring forward hourly", "0 * * * *", at(newYork, 2026, 3, 8, 1, 30), utc(2026, 3, 8, 7, 0)},
+		// 夏時間の終わり（2026-11-01 02:00 EDT → 01:00 EST）。1時台が2回あり、毎時の実行は実時間で1時間ごと
+		{"DST fall back hourly", "0 * * * *", utc(2026, 11, 1, 5, 30).In(newYork), utc(2026, 11, 1, 6, 0)},
+		{"DST fall back daily", "0 3 * * *", at(newYork, 2026, 10, 31, 3, 0), at(newYork, 2026, 11, 1, 3, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			if got := cron.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronNextNeverFires(t *testing.T) {
+	for _, expr := range []string{"0 0 30 2 *", "0 0 31 4 *", "0 0 31 2,4,6,9,11 *"} {
+		cron, err := ParseCron(expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", expr, err)
+		}
+		if got := cron.Next(time.Now()); !got.IsZero() {
+			t.Errorf("%q: Next = %v, want zero", expr, got)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package scheduler はDBの jobs テーブルで定期実行のジョブを管理します。
+// 実行時刻はDBに保存するので、再起動しても実行を逃さず、複数のプロセスが動いていても同じ回を2回実行しません。
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"self-management-bot/db"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+	"time"
+)
+
+const (
+	// pollInterval は実行時刻になったジョブを探す間隔です。
+	pollInterval = 15 * time.Second
+	// lockTTL はジョブを取ってから、そのプロセスが落ちたとみなすまでの時間です。これより長く実行するジョブは作らないでください。
+	lockTTL = 15 * time.Minute
+	// maxAttempts 回続けて失敗したら、その回はあきらめて次の予定時刻を待ちます。
+	maxAttempts = 5
+	// retryBackoff は失敗したときに再試行するまでの時間（試行回数倍）です。
+	retryBackoff = time.Minute
+)
+
+// Job は定期実行するジョブです。
+type Job struct {
+	Name     string // jobs テーブルのキー
+	Schedule string // cron式（ローカル時刻。例: "0 6,12,19 * * *"）
+	// MaxDelay より遅れた回は実行せず、次の予定時刻に回します（停止中に時刻を過ぎた朝のリマインドを昼に送らないため）。
+	// 0 なら、どれだけ遅れても1回だけ実行します（何回分遅れても1回です）。
+	MaxDelay time.Duration
+	Run      func(ctx context.Context) error
+}
+
+var (
+	jobs     = map[string]Job{}
+	crons    = map[string]Cron{}
+	workerID = fmt.Sprintf("%s-%d", hostname(), os.Getpid())
+)
+
+// hostname はプロセスを区別するためのホスト名です。
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
+
+// Register はジョブを登録します。Start の前に呼んでください。cron式が不正、または実行時刻が来ない式なら panic します。
+func Register(job Job) {
+	cron, err := ParseCron(job.Schedule)
+	if err != nil {
+		panic(err)
+	}
+	// 実行時刻が来ない式（2月30日など）はゼロ値が next_run_at に入り、毎回のポーリングで実行されてしまう
+	if cron.Next(time.Now()).IsZero() {
+		panic(fmt.Sprintf("job %q: cron %q never fires", job.Name, job.Schedule))
+	}
+	if _, ok := jobs[job.Name]; ok {
+		panic(fmt.Sprintf("job %q is already registered", job.Name))
+	}
+	jobs[job.Name] = job
+	crons[job.Name] = cron
+}
+
+// Start は登録したジョブを jobs テーブルに登録し、実行時刻になったものから実行します。
+func Start() error {
+	ctx := logging.NewContext(context.Background(), logging.Fields{Command: "scheduler"})
+	now := time.Now()
+	for name, job := range jobs {
+		next := crons[name].Next(now)
+		if err := repository.UpsertJob(ctx, name, job.Schedule, db.TimeValue(next)); err != nil {
+			return fmt.Errorf("register job %s: %w", name, err)
+		}
+	}
+	logging.From(ctx).Info("スケジューラ開始", "worker", workerID, "jobs", len(jobs))
+	go func() {
+		runDue(ctx)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runDue(ctx)
+		}
+	}()
+	return nil
+}
+
+// runDue は実行時刻になったジョブを、取れなくなるまで1つずつ取って実行します。
+func runDue(ctx context.Context) {
+	for {
+		now := time.Now()
+		claimed, ok, err := repository.ClaimDueJob(ctx, workerID, db.TimeValue(now), db.TimeValue(now.Add(lockTTL)))
+		if err != nil {
+			logging.From(ctx).Error("ジョブ取得エラー", "error", err)
+			return
+		}
+		if !ok {
+			return
+		}
+		run(ctx, claimed, now)
+	}
+}
+
+// run は取ったジョブを実行し、次回の実行時刻を記録します。
+func run(ctx context.Context, claimed repository.Job, now time.Time) {
+	logger := logging.From(ctx).With("job", claimed.Name, "attempt", claimed.Attempts)
+	job, ok := jobs[claimed.Name]
+	if !ok {
+		// 他のバージョンのプロセスが登録したジョブ。ロックが切れれば、そのジョブを知っているプロセスが実行する
+		logger.Warn("未登録のジョブ")
+		return
+	}
+	cron := crons[claimed.Name]
+	scheduled, err := db.ParseDateTimeText(claimed.NextRunAt)
+	if err != nil {
+		scheduled = now
+	}
+
+	var runErr error
+	if late := now.Sub(scheduled); job.MaxDelay > 0 && late > job.MaxDelay && claimed.Attempts == 1 {
+		logger.Warn("遅れすぎたため実行をスキップ", "scheduled", claimed.NextRunAt, "late", late.Round(time.Second))
+	} else {
+		start := time.Now()
+		runErr = runJob(logging.NewContext(context.Background(), logging.Fields{Command: job.Name}), job)
+		logger.Info("ジョブ実行", "duration_ms", time.Since(start).Milliseconds(), "error", runErr)
+	}
+
+	next := cron.Next(time.Now())
+	lastError, attempts := "", 0
+	if runErr != nil {
+		lastError = runErr.Error()
+		if claimed.Attempts < maxAttempts {
+			next = time.Now().Add(time.Duration(claimed.Attempts) * retryBackoff)
+			attempts = claimed.Attempts
+		} else {
+			logger.Error("再試行の上限に達したため次の予定時刻を待つ", "error", runErr)
+		}
+	}
+	if err := repository.FinishJob(ctx, claimed.Name, workerID, db.TimeValue(next), db.TimeValue(now), lastError, attempts); err != nil {
+		logger.Error("ジョブの記録に失敗", "error", err)
+	}
+}
+
+// runJob はジョブを実行します。panic してもプロセスを落とさず、エラーとして再試行の対象にします。
+func runJob(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.From(ctx).Error("panic in job", "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
@@ -0,0 +1,17 @@
+package service
+
+// Bot管理者向けの処理
+import (
+	"context"
+	"self-management-bot/repository"
+)
+
+// GetUserStatsService 全ユーザのタスク集計を取得
+func GetUserStatsService(ctx context.Context) ([]repository.UserStats, error) {
+	return repository.FindUserStats(ctx)
+}
+
+// GetBroadcastTargets お知らせの送信先（タスクを登録したことのある全ユーザ）を取得
+func GetBroadcastTargets(ctx context.Context) ([]string, error) {
+	return repository.FindAllUser(ctx)
+}
@@ -0,0 +1,134 @@
+package service
+
+// チームチャンネルに投稿するデイリーダイジェスト
+import (
+	"context"
+	"self-management-bot/client"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+	"strings"
+)
+
+// DigestMessage はチャンネルに投稿するダイジェストです。
+type DigestMessage struct {
+	GuildID   string
+	ChannelID string
+	Content   string
+}
+
+// JoinDigest はサーバーのデイリーダイジェストに参加します。
+func JoinDigest(ctx context.Context, guildID, userID string) error {
+	if guildID == "" {
+		return i18n.NewError("err.digest_guild_only")
+	}
+	return repository.AddDigestMember(ctx, guildID, userID)
+}
+
+// LeaveDigest はサーバーのデイリーダイジェストから抜けます。
+func LeaveDigest(ctx context.Context, guildID, userID string) error {
+	if guildID == "" {
+		return i18n.NewError("err.digest_guild_only")
+	}
+	return repository.RemoveDigestMember(ctx, guildID, userID)
+}
+
+// DailyDigests は投稿先が設定された全サーバーのダイジェストを作成します。
+// 1つのサーバーで失敗しても他のサーバーは続行します。
+func DailyDigests(ctx context.Context) ([]DigestMessage, error) {
+	logger := logging.From(ctx)
+	guilds, err := repository.FindDigestGuilds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var digests []DigestMessage
+	for _, g := range guilds {
+		content, err := buildDigest(ctx, g.GuildID)
+		if err != nil {
+			logger.Error("ダイジェスト作成失敗", "guild_id", g.GuildID, "error", err)
+			continue
+		}
+		if content == "" {
+			continue
+		}
+		digests = append(digests, DigestMessage{
+			GuildID:   g.GuildID,
+			ChannelID: g.DigestChannelID.String,
+			Content:   content,
+		})
+	}
+	return digests, nil
+}
+
+// buildDigest は参加メンバーの昨日の完了タスクと今日のP1タスクをまとめます。参加者がいなければ空文字です。
+func buildDigest(ctx context.Context, guildID string) (string, error) {
+	settings, err := GetSettings(ctx, guildID, "")
+	if err != nil {
+		return "", err
+	}
+	members, err := repository.FindDigestMembers(ctx, guildID)
+	if err != nil {
+		return "", err
+	}
+	if len(members) == 0 {
+		return "", nil
+	}
+
+	// ダイジェストはチャンネルに投稿するので、サーバーの言語で書く
+	lang := settings.Language
+	var msg strings.Builder
+	var summary strings.Builder // LLMに渡すチームの状況
+	msg.WriteString(i18n.T(lang, "digest.header"))
+	for i, userID := range members {
+		// 昨日作ったタスクではなく、昨日完了したタスク
+		completed, err := repository.FindCompletedYesterdayByUser(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		p1, err := repository.FindPendingTaskByPriority(ctx, userID, 1)
+		if err != nil {
+			return "", err
+		}
+		var done []string
+		for _, t := range completed {
+			done = append(done, t.Title)
+		}
+		msg.WriteString(i18n.T(lang, "digest.member", userID))
+		msg.WriteString(i18n.T(lang, "digest.done", joinOrNone(lang, done)))
+		var p1Titles []string
+		for _, t := range p1 {
+			p1Titles = append(p1Titles, t.Title)
+		}
+		msg.WriteString(i18n.T(lang, "digest.p1", joinOrNone(lang, p1Titles)))
+		summary.WriteString(i18n.T(lang, "prompt.peptalk.member", i+1, len(done), len(p1Titles)))
+	}
+
+	if settings.DigestPepTalk {
+		pep, err := createPepTalk(ctx, lang, summary.String())
+		if err != nil {
+			// 応援メッセージが無くてもダイジェストは投稿する
+			logging.From(ctx).Warn("応援メッセージの生成失敗", "guild_id", guildID, "error", err)
+		} else {
+			msg.WriteString("\n💬 " + pep + "\n")
+		}
+	}
+	return msg.String(), nil
+}
+
+// createPepTalk はチームの状況からLLMに短い応援メッセージを書かせます。
+func createPepTalk(ctx context.Context, lang, summary string) (string, error) {
+	var prompt strings.Builder
+	prompt.WriteString(i18n.T(lang, "prompt.peptalk.role"))
+	prompt.WriteString(i18n.T(lang, "prompt.peptalk.goal"))
+	prompt.WriteString(i18n.T(lang, "prompt.peptalk.status"))
+	prompt.WriteString(summary)
+	prompt.WriteString(i18n.T(lang, "prompt.answer_language"))
+	return client.GetGeminiResponse(ctx, prompt.String())
+}
+
+func joinOrNone(lang string, items []string) string {
+	if len(items) == 0 {
+		return i18n.T(lang, "digest.none")
+	}
+	return strings.Join(items, i18n.T(lang, "digest.separator"))
+}
@@ -0,0 +1,127 @@
+package service
+
+// 見積もりと実績の比較、今日の見積もりの合計
+import (
+	"context"
+	"self-management-bot/db"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"strings"
+	"time"
+)
+
+const (
+	// EstimateReportDays は見積もりと実績を比べる期間（日数）です。
+	EstimateReportDays = 90
+	// maxElapsedActualMinutes を超えて進行中だったタスクは、間に別のことをしていたとみなして実績に使いません。
+	maxElapsedActualMinutes = 12 * 60
+)
+
+// estimatePrefix は !add で見積もりを指定する語の接頭辞です（例: est:2h）。
+const estimatePrefix = "est:"
+
+// ParseEstimate は "est:2h" のような語を見積もりの分数にします。見積もりの語でなければ ok が false です。
+func ParseEstimate(word string) (minutes int, ok bool, err error) {
+	value, ok := strings.CutPrefix(strings.ToLower(word), estimatePrefix)
+	if !ok {
+		return 0, false, nil
+	}
+	minutes, err = ParseMinutes(value)
+	return minutes, true, err
+}
+
+// actualMinutes はタスクに実際にかかった分数を返します。
+// !track の記録、なければ集中した時間、なければ進行中にしてから完了するまでの時間を使います。どれも分からなければ ok が false です。
+func actualMinutes(r repository.EstimateResult) (int, bool) {
+	if r.TrackedMinutes > 0 {
+		return r.TrackedMinutes, true
+	}
+	if r.FocusMinutes > 0 {
+		return r.FocusMinutes, true
+	}
+	if !r.StartedAt.Valid || !r.CompletedAt.Valid {
+		return 0, false
+	}
+	started, err1 := db.ParseDateTimeText(r.StartedAt.String)
+	completed, err2 := db.ParseDateTimeText(r.CompletedAt.String)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	elapsed := int(completed.Sub(started).Minutes())
+	if elapsed <= 0 || elapsed > maxElapsedActualMinutes {
+		return 0, false
+	}
+	return elapsed, true
+}
+
+// EstimateRow は優先度ごとの見積もりと実績の合計です。
+type EstimateRow struct {
+	PriorityID int // 合計の行では 0
+	Count      int
+	Estimated  int
+	Actual     int
+}
+
+// EstimateReport は見積もりと実績の比較です。Unknown は実績が分からず比べられなかった件数です。
+type EstimateReport struct {
+	Since   string
+	Rows    []EstimateRow // 優先度の順
+	Total   EstimateRow
+	Unknown int
+}
+
+// EstimateReportService userID が直近 EstimateReportDays 日に完了したタスクの見積もりと実績を優先度ごとに比べる
+func EstimateReportService(ctx context.Context, userID string, now time.Time) (EstimateReport, error) {
+	report := EstimateReport{Since: now.AddDate(0, 0, -EstimateReportDays).Format(time.DateOnly)}
+	results, err := repository.FindEstimateResults(ctx, userID, report.Since)
+	if err != nil {
+		return report, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	// 結果は優先度の順に並んでいる
+	for _, r := range results {
+		actual, ok := actualMinutes(r)
+		if !ok {
+			report.Unknown++
+			continue
+		}
+		if n := len(report.Rows); n == 0 || report.Rows[n-1].PriorityID != r.PriorityID {
+			report.Rows = append(report.Rows, EstimateRow{PriorityID: r.PriorityID})
+		}
+		row := &report.Rows[len(report.Rows)-1]
+		row.Count++
+		row.Estimated += r.EstimateMinutes
+		row.Actual += actual
+		report.Total.Count++
+		report.Total.Estimated += r.EstimateMinutes
+		report.Total.Actual += actual
+	}
+	return report, nil
+}
+
+// DayPlan は今日の終わっていないタスクの見積もりの合計と、1日に使える時間（分）です。
+type DayPlan struct {
+	Estimated   int
+	Available   int
+	Unestimated int // 見積もりのないタスクの件数
+}
+
+// Overcommitted は見積もりの合計が使える時間を超えているかどうかです。
+func (p DayPlan) Overcommitted() bool {
+	return p.Estimated > p.Available
+}
+
+// TodayPlanService userID の個人リストの終わっていないタスクの見積もりを合計する
+func TodayPlanService(ctx context.Context, userID string) (DayPlan, error) {
+	plan := DayPlan{Available: AvailableMinutes(ctx, userID)}
+	tasks, err := repository.FindPendingTaskByUser(ctx, userID)
+	if err != nil {
+		return plan, err
+	}
+	for _, t := range tasks {
+		if t.EstimateMinutes == 0 {
+			plan.Unestimated++
+		}
+		plan.Estimated += t.EstimateMinutes
+	}
+	return plan, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+// 集中（ポモドーロ）と休憩のタイマー
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"self-management-bot/db"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+	"time"
+)
+
+const (
+	// DefaultFocusMinutes / DefaultBreakMinutes は分数を省略したときのタイマーの長さです。
+	DefaultFocusMinutes = 25
+	DefaultBreakMinutes = 5
+	// MaxFocusMinutes はタイマーに指定できる最大の分数です。
+	MaxFocusMinutes = 180
+)
+
+// FocusStart は始めたタイマーです。
+type FocusStart struct {
+	Task   repository.DatedTask // 休憩では空
+	EndsAt time.Time
+}
+
+// validateTimer はタイマーの分数と、他のタイマーが動いていないことを確認します。
+func validateTimer(ctx context.Context, userID string, minutes int) error {
+	if minutes <= 0 || minutes > MaxFocusMinutes {
+		return i18n.NewError("err.focus_minutes", MaxFocusMinutes)
+	}
+	_, err := repository.FindRunningFocusSession(ctx, userID)
+	if err == nil {
+		return i18n.NewError("err.focus_running")
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return i18n.WrapError(err, "err.focus_save")
+	}
+	return nil
+}
+
+// startTimerError はタイマーを始められなかったエラーを返します。
+// validateTimer の確認のあとに別のコマンドでタイマーが始まっていれば、一意制約で弾かれるので実行中として扱います。
+func startTimerError(err error) error {
+	if db.IsUniqueViolation(err) {
+		return i18n.NewError("err.focus_running")
+	}
+	return i18n.WrapError(err, "err.focus_save")
+}
+
+// StartFocusService actor がタスクに minutes 分集中するタイマーを始める
+func StartFocusService(ctx context.Context, actor Actor, guildID string, taskID, minutes int, now time.Time) (FocusStart, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return FocusStart{}, err
+	}
+	if !task.IsOpen() {
+		return FocusStart{}, i18n.NewError("err.defer_closed", taskID)
+	}
+	if err := validateTimer(ctx, actor.UserID, minutes); err != nil {
+		return FocusStart{}, err
+	}
+	endsAt := now.Add(time.Duration(minutes) * time.Minute)
+	err = repository.StartFocusSession(ctx, actor.UserID, taskID, repository.FocusKindFocus, minutes, db.TimeValue(now), db.TimeValue(endsAt))
+	if err != nil {
+		return FocusStart{}, startTimerError(err)
+	}
+	return FocusStart{Task: task, EndsAt: endsAt}, nil
+}
+
+// StartBreakService userID の minutes 分の休憩タイマーを始める
+func StartBreakService(ctx context.Context, userID string, minutes int, now time.Time) (FocusStart, error) {
+	if err := validateTimer(ctx, userID, minutes); err != nil {
+		return FocusStart{}, err
+	}
+	endsAt := now.Add(time.Duration(minutes) * time.Minute)
+	err := repository.StartFocusSession(ctx, userID, 0, repository.FocusKindBreak, minutes, db.TimeValue(now), db.TimeValue(endsAt))
+	if err != nil {
+		return FocusStart{}, startTimerError(err)
+	}
+	return FocusStart{EndsAt: endsAt}, nil
+}
+
+// StopFocusService userID の実行中のタイマーを止める。集中は止めるまでの分数を記録する
+func StopFocusService(ctx context.Context, userID string, now time.Time) (repository.FocusSession, error) {
+	session, err := repository.FindRunningFocusSession(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return session, i18n.NewError("err.focus_not_running")
+	}
+	if err != nil {
+		return session, i18n.WrapError(err, "err.focus_save")
+	}
+	if started, err := db.ParseDateTimeText(session.StartedAt); err == nil {
+		session.Minutes = min(session.Minutes, max(0, int(now.Sub(started).Minutes())))
+	}
+	if err := repository.StopFocusSession(ctx, session.ID, session.Minutes, db.TimeValue(now)); err != nil {
+		return session, i18n.WrapError(err, "err.focus_save")
+	}
+	return session, nil
+}
+
+// FocusNotice はタイマー終了の通知と、送った結果を記録するためのIDと試行回数です。
+type FocusNotice struct {
+	ReminderMessage
+	ID       int
+	Attempts int
+}
+
+// EndedFocusService now までに終わったタイマーを終了済みにし、終了の通知（DM）を作る
+// 送った結果は FocusNoticeSentService / FocusNoticeFailedService で記録する
+func EndedFocusService(ctx context.Context, now time.Time) ([]FocusNotice, error) {
+	sessions, err := repository.ClaimEndedFocusSessions(ctx, db.TimeValue(now), db.TimeValue(now.Add(reminderLease)))
+	if err != nil && len(sessions) == 0 {
+		return nil, err
+	}
+	if err != nil {
+		logging.From(ctx).Error("タイマー終了処理の途中でエラー", "error", err)
+	}
+	notices := make([]FocusNotice, 0, len(sessions))
+	for _, session := range sessions {
+		lang := UserLanguage(ctx, session.UserID, i18n.Default)
+		content := i18n.T(lang, "break.ended", session.Minutes, DefaultFocusMinutes)
+		if session.Kind == repository.FocusKindFocus {
+			content = i18n.T(lang, "focus.ended", session.TaskID.Int64, session.Title, session.Minutes, DefaultBreakMinutes)
+		}
+		notices = append(notices, FocusNotice{
+			ReminderMessage: ReminderMessage{UserID: session.UserID, Content: content},
+			ID:              session.ID,
+			Attempts:        session.Attempts,
+		})
+	}
+	return notices, nil
+}
+
+// FocusNoticeSentService 送れたタイマー終了の通知を通知済みにする
+func FocusNoticeSentService(ctx context.Context, n FocusNotice, now time.Time) error {
+	return repository.MarkFocusNotified(ctx, n.ID, db.TimeValue(now))
+}
+
+// FocusNoticeFailedService 送れなかったタイマー終了の通知を、間をあけて送り直すようにする。上限に達したらあきらめて giveUp を返す
+func FocusNoticeFailedService(ctx context.Context, n FocusNotice, now time.Time) (giveUp bool, err error) {
+	retryAt, giveUp := nextRetry(n.Attempts, now)
+	if giveUp {
+		return true, repository.FailFocusNotice(ctx, n.ID, db.TimeValue(now))
+	}
+	return false, repository.RetryFocusNotice(ctx, n.ID, db.TimeValue(retryAt))
+}
+
+// FocusMinutesTodayService userID が今日集中した合計分数
+func FocusMinutesTodayService(ctx context.Context, userID string) (int, error) {
+	return repository.FocusMinutesOn(ctx, userID, time.Now().Format(time.DateOnly))
+}
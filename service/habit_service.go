@@ -0,0 +1,170 @@
+package service
+
+// 習慣のチェックと連続日数
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxHabitNameLength は習慣の名前の最大文字数です。
+const MaxHabitNameLength = 50
+
+// HabitStatus は習慣のチェックの記録と連続日数です。
+type HabitStatus struct {
+	repository.Habit
+	// Days はチェックした日（'YYYY-MM-DD'）を古い順に並べたものです。
+	Days []string
+	// Current は今日（今日まだなら昨日）まで続いている連続日数、Longest はこれまでの最長の連続日数です。
+	Current, Longest int
+	// Today / Yesterday は今日・昨日チェックしたかどうかです。
+	Today, Yesterday bool
+}
+
+// CheckedOn は day（'YYYY-MM-DD'）にチェックしたかどうかを返します。
+func (s HabitStatus) CheckedOn(day string) bool {
+	_, found := slices.BinarySearch(s.Days, day)
+	return found
+}
+
+// newHabitStatus はチェックした日から連続日数を求めます。
+// 今日まだチェックしていなくても、昨日まで続いていれば途切れたとはみなしません。
+func newHabitStatus(habit repository.Habit, days []string, now time.Time) HabitStatus {
+	today := now.Format(time.DateOnly)
+	yesterday := now.AddDate(0, 0, -1).Format(time.DateOnly)
+	status := HabitStatus{Habit: habit, Days: days}
+	status.Today = status.CheckedOn(today)
+	status.Yesterday = status.CheckedOn(yesterday)
+
+	run := 0
+	var prev time.Time
+	for _, d := range days {
+		day, err := time.ParseInLocation(time.DateOnly, d, now.Location())
+		if err != nil {
+			continue
+		}
+		if run > 0 && prev.AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		prev = day
+		status.Longest = max(status.Longest, run)
+	}
+	if last := len(days) - 1; last >= 0 && (days[last] == today || days[last] == yesterday) {
+		status.Current = run
+	}
+	return status
+}
+
+// findHabit は userID の習慣を名前で探します。
+func findHabit(ctx context.Context, userID, name string) (repository.Habit, error) {
+	habit, err := repository.FindHabitByName(ctx, userID, strings.TrimSpace(name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return habit, i18n.NewError("err.habit_not_found", name)
+	}
+	if err != nil {
+		return habit, i18n.WrapError(err, "err.habit_fetch")
+	}
+	return habit, nil
+}
+
+// AddHabitService userID の習慣を追加する
+func AddHabitService(ctx context.Context, userID, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxHabitNameLength {
+		return i18n.NewError("err.habit_name", MaxHabitNameLength)
+	}
+	_, err := repository.FindHabitByName(ctx, userID, name)
+	if err == nil {
+		return i18n.NewError("err.habit_exists", name)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return i18n.WrapError(err, "err.habit_save")
+	}
+	if err := repository.AddHabit(ctx, userID, name); err != nil {
+		return i18n.WrapError(err, "err.habit_save")
+	}
+	return nil
+}
+
+// CheckHabitService userID の習慣を今日やったと記録し、記録後の連続日数を返す。今日すでに記録済みならエラー
+func CheckHabitService(ctx context.Context, userID, name string, now time.Time) (HabitStatus, error) {
+	habit, err := findHabit(ctx, userID, name)
+	if err != nil {
+		return HabitStatus{}, err
+	}
+	added, err := repository.CheckInHabit(ctx, habit.ID, now.Format(time.DateOnly))
+	if err != nil {
+		return HabitStatus{}, i18n.WrapError(err, "err.habit_save")
+	}
+	if !added {
+		return HabitStatus{}, i18n.NewError("err.habit_checked", habit.Name)
+	}
+	return habitStatus(ctx, habit, now)
+}
+
+// DeleteHabitService userID の習慣をチェックの記録ごと削除する
+func DeleteHabitService(ctx context.Context, userID, name string) (repository.Habit, error) {
+	habit, err := findHabit(ctx, userID, name)
+	if err != nil {
+		return habit, err
+	}
+	if err := repository.DeleteHabit(ctx, habit.ID); err != nil {
+		return habit, i18n.WrapError(err, "err.habit_save")
+	}
+	return habit, nil
+}
+
+// HabitStatusService userID の習慣1つの記録と連続日数を取得する
+func HabitStatusService(ctx context.Context, userID, name string, now time.Time) (HabitStatus, error) {
+	habit, err := findHabit(ctx, userID, name)
+	if err != nil {
+		return HabitStatus{}, err
+	}
+	return habitStatus(ctx, habit, now)
+}
+
+func habitStatus(ctx context.Context, habit repository.Habit, now time.Time) (HabitStatus, error) {
+	days, err := repository.FindCheckInDays(ctx, []int{habit.ID})
+	if err != nil {
+		return HabitStatus{}, i18n.WrapError(err, "err.habit_fetch")
+	}
+	return newHabitStatus(habit, days[habit.ID], now), nil
+}
+
+// HabitStatusesService userID のすべての習慣の記録と連続日数を追加した順に取得する
+func HabitStatusesService(ctx context.Context, userID string, now time.Time) ([]HabitStatus, error) {
+	habits, err := repository.FindHabits(ctx, userID)
+	if err != nil {
+		return nil, i18n.WrapError(err, "err.habit_fetch")
+	}
+	ids := make([]int, len(habits))
+	for i, h := range habits {
+		ids[i] = h.ID
+	}
+	days, err := repository.FindCheckInDays(ctx, ids)
+	if err != nil {
+		return nil, i18n.WrapError(err, "err.habit_fetch")
+	}
+	statuses := make([]HabitStatus, len(habits))
+	for i, h := range habits {
+		statuses[i] = newHabitStatus(h, days[h.ID], now)
+	}
+	return statuses, nil
+}
+
+// HabitLine は習慣1つの状態の1行（今日チェックしたか・連続日数・最長）です。
+func HabitLine(lang string, s HabitStatus) string {
+	mark := "⬜"
+	if s.Today {
+		mark = "✅"
+	}
+	return i18n.T(lang, "habit.line", mark, s.Name, s.Current, s.Longest)
+}
@@ -0,0 +1,58 @@
+package service
+
+// 共有リスト（チャンネル単位のタスクリスト）関連の処理
+import (
+	"context"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+)
+
+// Actor はコマンドを実行したユーザと、その権限です。
+type Actor struct {
+	UserID  string
+	Manager bool // 共有リストの管理者か
+	// ChannelID はコマンドを実行したチャンネルです。共有リストのタスクは、このチャンネルのものだけを見られます。
+	ChannelID string
+}
+
+// CanModify は task を編集・削除できるかどうかを返します。
+// 共有リストのタスクは、作成者・担当者・管理者のみが変更できます。
+func (a Actor) CanModify(task repository.Task) bool {
+	if task.Scope != repository.ScopeChannel {
+		return true
+	}
+	return a.Manager || task.CreatedBy == a.UserID || task.AssigneeID == a.UserID
+}
+
+// ResolveScope はコマンドがどのリストに対して動くかを決めます。
+// team が true（@team 指定）か、チャンネルが共有リストモードならチャンネルのリスト、それ以外は個人のリストです。
+func ResolveScope(ctx context.Context, guildID, channelID, userID string, team bool) (repository.Scope, error) {
+	if guildID == "" {
+		if team {
+			return repository.Scope{}, i18n.NewError("err.shared_guild_only")
+		}
+		return repository.PersonalScope(userID), nil
+	}
+	if team {
+		return repository.ChannelScope(guildID, channelID), nil
+	}
+	shared, err := repository.IsChannelList(ctx, channelID)
+	if err != nil {
+		return repository.Scope{}, i18n.WrapError(err, "err.channel_list_fetch")
+	}
+	if shared {
+		return repository.ChannelScope(guildID, channelID), nil
+	}
+	return repository.PersonalScope(userID), nil
+}
+
+// SetChannelListMode はチャンネルの共有リストモードを切り替えます。
+func SetChannelListMode(ctx context.Context, guildID, channelID, userID string, enabled bool) error {
+	if guildID == "" {
+		return i18n.NewError("err.shared_guild_only")
+	}
+	if enabled {
+		return repository.EnableChannelList(ctx, guildID, channelID, userID)
+	}
+	return repository.DisableChannelList(ctx, channelID)
+}
@@ -0,0 +1,84 @@
+package service
+
+// タスクIDで指定するタスクの詳細・メモ・状態の履歴
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"strings"
+)
+
+// TaskDetail は !show で表示するタスクの詳細です。
+type TaskDetail struct {
+	Task   repository.DatedTask
+	Notes  []repository.TaskNote
+	Events []repository.TaskEvent
+	// Reminders は actor が登録した未送信のリマインドです。
+	Reminders []repository.PendingReminder
+	// FocusMinutes はタスクに集中した合計分数です。
+	FocusMinutes int
+}
+
+// canSee は actor が task を見られるかどうかを返します。
+// 個人のタスクは持ち主と依頼者、共有リストのタスクはそのリストのチャンネルで実行したユーザが見られます。
+// 見られないチャンネル（プライベートチャンネルなど）のリストを、IDを当てて読み書きできないようにするためです。
+func canSee(actor Actor, guildID string, task repository.Task) bool {
+	if task.Scope == repository.ScopeChannel {
+		return guildID != "" && task.GuildID == guildID && task.ScopeID == actor.ChannelID
+	}
+	return task.UserID == actor.UserID || task.CreatedBy == actor.UserID
+}
+
+// GetTaskByIDService タスクIDで actor から見えるタスクを取得する。見えないタスクは存在しないものとして扱う
+func GetTaskByIDService(ctx context.Context, actor Actor, guildID string, taskID int) (repository.DatedTask, error) {
+	task, err := repository.FindTaskByID(ctx, taskID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !canSee(actor, guildID, task.Task)) {
+		return repository.DatedTask{}, i18n.NewError("err.task_not_found", taskID)
+	}
+	if err != nil {
+		return repository.DatedTask{}, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	return task, nil
+}
+
+// AddNoteService タスクにメモを追記する
+func AddNoteService(ctx context.Context, actor Actor, guildID string, taskID int, body string) (repository.DatedTask, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return task, err
+	}
+	if !actor.CanModify(task.Task) {
+		return task, i18n.NewError("err.shared_edit")
+	}
+	if err := repository.AddTaskNote(ctx, taskID, actor.UserID, strings.TrimSpace(body)); err != nil {
+		return task, i18n.WrapError(err, "err.note_save")
+	}
+	return task, nil
+}
+
+// GetTaskDetailService タスクの詳細とメモを取得する
+func GetTaskDetailService(ctx context.Context, actor Actor, guildID string, taskID int) (TaskDetail, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return TaskDetail{}, err
+	}
+	notes, err := repository.FindNotesByTask(ctx, taskID)
+	if err != nil {
+		return TaskDetail{}, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	events, err := repository.FindEventsByTask(ctx, taskID)
+	if err != nil {
+		return TaskDetail{}, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	reminders, err := repository.FindPendingReminders(ctx, taskID, actor.UserID)
+	if err != nil {
+		return TaskDetail{}, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	focus, err := repository.FocusMinutesByTask(ctx, []int{taskID})
+	if err != nil {
+		return TaskDetail{}, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	return TaskDetail{Task: task, Notes: notes, Events: events, Reminders: reminders, FocusMinutes: focus[taskID]}, nil
+}
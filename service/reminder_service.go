@@ -0,0 +1,137 @@
+package service
+
+// タスクごとのリマインド（!remind）
+import (
+	"context"
+	"regexp"
+	"self-management-bot/db"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+	"strings"
+	"time"
+)
+
+// clockPattern は "15:30" のような時刻です。
+var clockPattern = regexp.MustCompile(`^([01]?\d|2[0-3]):([0-5]\d)$`)
+
+// ParseRemindTime "15:30"（過ぎていれば翌日）または "in 2h" / "in 30m" / "in 1h30m" を now 以降の時刻にする
+func ParseRemindTime(text string, now time.Time) (time.Time, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if m := clockPattern.FindStringSubmatch(text); m != nil {
+		at, _ := time.ParseInLocation("15:04", m[1]+":"+m[2], now.Location())
+		at = time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+	if rest, ok := strings.CutPrefix(text, "in "); ok {
+		d, err := time.ParseDuration(strings.ReplaceAll(rest, " ", ""))
+		if err == nil && d >= time.Minute {
+			return now.Add(d).Truncate(time.Minute), nil
+		}
+	}
+	return time.Time{}, i18n.NewError("err.invalid_remind_time", text)
+}
+
+// SetReminderService actor にタスクのリマインドを at にDMするよう登録する
+func SetReminderService(ctx context.Context, actor Actor, guildID string, taskID int, at time.Time) (repository.DatedTask, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return task, err
+	}
+	if !task.IsOpen() {
+		return task, i18n.NewError("err.defer_closed", taskID)
+	}
+	if err := repository.AddReminder(ctx, taskID, actor.UserID, db.TimeValue(at)); err != nil {
+		return task, i18n.WrapError(err, "err.remind_save")
+	}
+	return task, nil
+}
+
+// CancelRemindersService actor がタスクに登録した未送信のリマインドを取り消す
+func CancelRemindersService(ctx context.Context, actor Actor, guildID string, taskID int) (repository.DatedTask, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return task, err
+	}
+	n, err := repository.DeleteReminders(ctx, taskID, actor.UserID)
+	if err != nil {
+		return task, i18n.WrapError(err, "err.remind_save")
+	}
+	if n == 0 {
+		return task, i18n.NewError("err.no_reminders", taskID)
+	}
+	return task, nil
+}
+
+const (
+	// reminderLease は取ったリマインドを送信中とみなす時間です。過ぎたら他のプロセスが取り直します。
+	reminderLease = 5 * time.Minute
+	// reminderMaxAttempts 回送れなかったリマインドはあきらめます。
+	reminderMaxAttempts = 5
+	// reminderRetryBackoff × 試行回数だけあけて送り直します。
+	reminderRetryBackoff = time.Minute
+)
+
+// DueReminder は送るリマインドと、送った結果を記録するためのIDと試行回数です。
+type DueReminder struct {
+	ReminderMessage
+	ID       int
+	Attempts int
+}
+
+// DueRemindersService now までに送るべきリマインドを取り出し、送る内容を作る
+// 送った結果は ReminderSentService / ReminderFailedService で記録する。その間に終わったタスクのリマインドは送らずに送信済みにする
+func DueRemindersService(ctx context.Context, now time.Time) ([]DueReminder, error) {
+	due, err := repository.ClaimDueReminders(ctx, db.TimeValue(now), db.TimeValue(now.Add(reminderLease)))
+	if err != nil && len(due) == 0 {
+		return nil, err
+	}
+	if err != nil {
+		logging.From(ctx).Error("リマインド取り出しの途中でエラー", "error", err)
+	}
+	reminders := make([]DueReminder, 0, len(due))
+	for _, r := range due {
+		if !(repository.Task{Status: r.Status}).IsOpen() {
+			if err := repository.MarkReminderSent(ctx, r.ID, db.TimeValue(now)); err != nil {
+				logging.From(ctx).Error("リマインドの記録に失敗", "reminder_id", r.ID, "error", err)
+			}
+			continue
+		}
+		lang := UserLanguage(ctx, r.UserID, i18n.Default)
+		reminders = append(reminders, DueReminder{
+			ReminderMessage: ReminderMessage{
+				UserID:  r.UserID,
+				Content: i18n.T(lang, "remind.due", r.TaskID, r.Title),
+			},
+			ID:       r.ID,
+			Attempts: r.Attempts,
+		})
+	}
+	return reminders, nil
+}
+
+// ReminderSentService 送れたリマインドを送信済みにする
+func ReminderSentService(ctx context.Context, r DueReminder, now time.Time) error {
+	return repository.MarkReminderSent(ctx, r.ID, db.TimeValue(now))
+}
+
+// ReminderFailedService 送れなかったリマインドを、間をあけて送り直すようにする。上限に達したらあきらめて giveUp を返す
+func ReminderFailedService(ctx context.Context, r DueReminder, now time.Time) (giveUp bool, err error) {
+	retryAt, giveUp := nextRetry(r.Attempts, now)
+	if giveUp {
+		return true, repository.FailReminder(ctx, r.ID, db.TimeValue(now))
+	}
+	return false, repository.RetryReminder(ctx, r.ID, db.TimeValue(retryAt))
+}
+
+// nextRetry は attempts 回目の送信に失敗したとき、次に送り直す時刻を返します。上限に達していれば giveUp が true です。
+// リマインドとタイマー終了の通知で共通です。
+func nextRetry(attempts int, now time.Time) (retryAt time.Time, giveUp bool) {
+	if attempts >= reminderMaxAttempts {
+		return time.Time{}, true
+	}
+	return now.Add(time.Duration(attempts) * reminderRetryBackoff), false
+}
@@ -0,0 +1,79 @@
+package service
+
+// 日をまたいだタスクの持ち越しと後回し
+import (
+	"context"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChronicCarryOverDays 日以上持ち越したタスク、または ChronicDeferCount 回以上後回しにしたタスクを
+// 先延ばしが続いているタスクとしてリマインドで取り上げます。
+const (
+	ChronicCarryOverDays = 3
+	ChronicDeferCount    = 2
+)
+
+// RolloverService 終わっていないタスクの持ち越し日数を数える（1日1回）
+func RolloverService(ctx context.Context) (int, error) {
+	return repository.RolloverTasks(ctx)
+}
+
+// ParseFutureDate "tomorrow" / "明日"、"3d" のような日数、"2w" のような週数、または 'YYYY-MM-DD' を
+// now より後の日付（'YYYY-MM-DD'）にする
+func ParseFutureDate(word string, now time.Time) (string, error) {
+	today := now.Format(time.DateOnly)
+	lower := strings.ToLower(word)
+	switch lower {
+	case "tomorrow", "明日":
+		return now.AddDate(0, 0, 1).Format(time.DateOnly), nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(lower, "d")); err == nil && strings.HasSuffix(lower, "d") && n > 0 {
+		return now.AddDate(0, 0, n).Format(time.DateOnly), nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(lower, "w")); err == nil && strings.HasSuffix(lower, "w") && n > 0 {
+		return now.AddDate(0, 0, 7*n).Format(time.DateOnly), nil
+	}
+	date, err := time.Parse(time.DateOnly, word)
+	if err != nil {
+		return "", i18n.NewError("err.invalid_future_date", word)
+	}
+	if d := date.Format(time.DateOnly); d > today {
+		return d, nil
+	}
+	return "", i18n.NewError("err.date_not_future", word)
+}
+
+// DeferTasksService 複数のタスクをまとめて until まで後回しにする。1件でも後回しにできなければ何も変えない
+func DeferTasksService(ctx context.Context, actor Actor, guildID string, taskIDs []int, until string) ([]repository.DatedTask, error) {
+	tasks := make([]repository.DatedTask, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		task, err := GetTaskByIDService(ctx, actor, guildID, id)
+		if err != nil {
+			return nil, err
+		}
+		if !actor.CanModify(task.Task) {
+			return nil, i18n.NewError("err.shared_edit")
+		}
+		if !task.IsOpen() {
+			return nil, i18n.NewError("err.defer_closed", id)
+		}
+		tasks = append(tasks, task)
+	}
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	if err := repository.DeferTasks(ctx, ids, until); err != nil {
+		return nil, i18n.WrapError(err, "err.defer_save")
+	}
+	return tasks, nil
+}
+
+// IsChronic は先延ばしが続いているタスクかどうかを返します。
+func IsChronic(task repository.Task) bool {
+	return task.CarryOverDays >= ChronicCarryOverDays || task.DeferCount >= ChronicDeferCount
+}
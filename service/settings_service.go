@@ -0,0 +1,159 @@
+package service
+
+// サーバー/チャンネルごとのBot設定
+import (
+	"context"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"strings"
+	"sync"
+)
+
+const (
+	ReplyModeChannel = "channel" // 実行したチャンネルに返信
+	ReplyModeDM      = "dm"      // DMで返信
+)
+
+// Settings は既定値・サーバー設定・チャンネル設定を重ねた、実際に使われる設定です。
+type Settings struct {
+	Prefix          string
+	DigestChannelID string
+	ReplyMode       string
+	Language        string
+	ChatEnabled     bool
+	DigestPepTalk   bool // ダイジェストにLLMの応援メッセージを付けるか
+}
+
+// DefaultSettings はDMや未設定のサーバーで使われる設定です。
+var DefaultSettings = Settings{
+	Prefix:      "!",
+	ReplyMode:   ReplyModeChannel,
+	Language:    i18n.Default,
+	ChatEnabled: true,
+}
+
+// settingsCache はメッセージごとにDBを引かないためのキャッシュです（キー: guildID/channelID）。
+var settingsCache sync.Map
+
+// GetSettings はサーバーとチャンネルの実効設定を返します。
+func GetSettings(ctx context.Context, guildID, channelID string) (Settings, error) {
+	if guildID == "" {
+		return DefaultSettings, nil
+	}
+	key := guildID + "/" + channelID
+	if cached, ok := settingsCache.Load(key); ok {
+		return cached.(Settings), nil
+	}
+	rows, err := repository.FindGuildSettings(ctx, guildID, channelID)
+	if err != nil {
+		return DefaultSettings, err
+	}
+	settings := DefaultSettings
+	// サーバー全体の設定 → チャンネルの上書きの順に適用する
+	for _, onlyChannel := range []bool{false, true} {
+		for _, row := range rows {
+			if (row.ChannelID != "") != onlyChannel {
+				continue
+			}
+			if row.Prefix.Valid {
+				settings.Prefix = row.Prefix.String
+			}
+			if row.DigestChannelID.Valid {
+				settings.DigestChannelID = row.DigestChannelID.String
+			}
+			if row.ReplyMode.Valid {
+				settings.ReplyMode = row.ReplyMode.String
+			}
+			if row.Language.Valid {
+				settings.Language = row.Language.String
+			}
+			if row.ChatEnabled.Valid {
+				settings.ChatEnabled = row.ChatEnabled.Bool
+			}
+			if row.DigestPepTalk.Valid {
+				settings.DigestPepTalk = row.DigestPepTalk.Bool
+			}
+		}
+	}
+	settingsCache.Store(key, settings)
+	return settings, nil
+}
+
+// SettingKeys は !config で変更できる項目と、チャンネル単位で上書きできるかどうかです。
+var SettingKeys = map[string]bool{
+	"prefix":  false,
+	"digest":  false,
+	"reply":   true,
+	"lang":    true,
+	"chat":    true,
+	"peptalk": false,
+}
+
+// settingColumn は !config の項目名と guild_settings のカラムの対応です。
+var settingColumn = map[string]string{
+	"prefix":  "prefix",
+	"digest":  "digest_channel_id",
+	"reply":   "reply_mode",
+	"lang":    "language",
+	"chat":    "chat_enabled",
+	"peptalk": "digest_pep_talk",
+}
+
+// UpdateSetting は !config の入力を検証して設定を保存します。channelID が空ならサーバー全体の設定です。
+// value に "reset" を指定すると未設定（上位の設定を使う）に戻します。
+func UpdateSetting(ctx context.Context, guildID, channelID, key, value string) error {
+	if guildID == "" {
+		return i18n.NewError("err.settings_guild_only")
+	}
+	perChannel, ok := SettingKeys[key]
+	if !ok {
+		return i18n.NewError("err.unknown_setting", key)
+	}
+	if channelID != "" && !perChannel {
+		return i18n.NewError("err.setting_not_channel", key)
+	}
+
+	column := settingColumn[key]
+	var stored interface{}
+	switch {
+	case value == "reset":
+		stored = nil
+	case key == "prefix":
+		if len([]rune(value)) > 3 || strings.ContainsAny(value, " \t\n") {
+			return i18n.NewError("err.prefix_invalid")
+		}
+		stored = value
+	case key == "digest":
+		stored = value
+		if value == "off" {
+			stored = ""
+		}
+	case key == "reply":
+		if value != ReplyModeChannel && value != ReplyModeDM {
+			return i18n.NewError("err.reply_invalid")
+		}
+		stored = value
+	case key == "lang":
+		if !i18n.IsSupported(value) {
+			return i18n.NewError("err.lang_invalid", strings.Join(i18n.Supported(), ", "))
+		}
+		stored = value
+	case key == "chat", key == "peptalk":
+		if value != "on" && value != "off" {
+			return i18n.NewError("err.onoff_invalid", key)
+		}
+		stored = value == "on"
+	}
+
+	if err := repository.UpsertGuildSetting(ctx, guildID, channelID, column, stored); err != nil {
+		return i18n.WrapError(err, "err.settings_save")
+	}
+	// サーバー設定の変更はそのサーバーの全チャンネルに影響するので、まとめて破棄する
+	settingsCache.Range(func(k, _ any) bool {
+		if strings.HasPrefix(k.(string), guildID+"/") {
+			settingsCache.Delete(k)
+		}
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,69 @@
+package service
+
+// タスクのスヌーズ（指定日まで一覧・リマインドから隠す）
+import (
+	"context"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+)
+
+// SnoozeNotice はスヌーズが明けたタスクの通知です。ChannelID が空なら UserID にDMで送ります。
+type SnoozeNotice struct {
+	UserID    string
+	ChannelID string
+	Content   string
+}
+
+// SnoozeTaskService タスクを until（'YYYY-MM-DD'）まで隠す。until が空ならスヌーズを解除する
+func SnoozeTaskService(ctx context.Context, actor Actor, guildID string, taskID int, until string) (repository.DatedTask, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return task, err
+	}
+	if !actor.CanModify(task.Task) {
+		return task, i18n.NewError("err.shared_edit")
+	}
+	if !task.IsOpen() {
+		return task, i18n.NewError("err.defer_closed", taskID)
+	}
+	if until == "" && !task.SnoozedUntil.Valid {
+		return task, i18n.NewError("err.not_snoozed", taskID)
+	}
+	if err := repository.SnoozeTask(ctx, taskID, until); err != nil {
+		return task, i18n.WrapError(err, "err.snooze_save")
+	}
+	return task, nil
+}
+
+// WakeSnoozedService スヌーズが明けたタスクを一覧に戻し、通知を作る
+// 個人のタスクは持ち主にその人の言語でDMし、共有リストのタスクはチャンネルにサーバーの言語で投稿する
+func WakeSnoozedService(ctx context.Context) ([]SnoozeNotice, error) {
+	tasks, err := repository.WakeSnoozedTasks(ctx)
+	if err != nil && len(tasks) == 0 {
+		return nil, err
+	}
+	if err != nil {
+		logging.From(ctx).Error("スヌーズ解除の途中でエラー", "error", err)
+	}
+	notices := make([]SnoozeNotice, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Scope == repository.ScopeChannel {
+			settings, err := GetSettings(ctx, task.GuildID, task.ScopeID)
+			if err != nil {
+				logging.From(ctx).Warn("設定取得失敗", "guild_id", task.GuildID, "error", err)
+			}
+			notices = append(notices, SnoozeNotice{
+				ChannelID: task.ScopeID,
+				Content:   i18n.T(settings.Language, "snooze.woke", task.ID, task.Title),
+			})
+			continue
+		}
+		lang := UserLanguage(ctx, task.UserID, i18n.Default)
+		notices = append(notices, SnoozeNotice{
+			UserID:  task.UserID,
+			Content: i18n.T(lang, "snooze.woke", task.ID, task.Title),
+		})
+	}
+	return notices, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+// タスクの状態遷移
+import (
+	"context"
+	"errors"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"slices"
+)
+
+// transitions は状態ごとに遷移できる先の状態です。
+// blocked のタスクはいったんブロックを解除（todo / in_progress）してから完了にします。
+var transitions = map[string][]string{
+	repository.StatusTodo:       {repository.StatusInProgress, repository.StatusBlocked, repository.StatusWaiting, repository.StatusDone, repository.StatusCancelled},
+	repository.StatusInProgress: {repository.StatusTodo, repository.StatusBlocked, repository.StatusWaiting, repository.StatusDone, repository.StatusCancelled},
+	repository.StatusBlocked:    {repository.StatusTodo, repository.StatusInProgress, repository.StatusWaiting, repository.StatusCancelled},
+	repository.StatusWaiting:    {repository.StatusTodo, repository.StatusInProgress, repository.StatusBlocked, repository.StatusDone, repository.StatusCancelled},
+	repository.StatusDone:       {repository.StatusTodo},
+	repository.StatusCancelled:  {repository.StatusTodo},
+}
+
+// ValidateTransition は from から to に変えられるかを検証します。
+func ValidateTransition(from, to string) error {
+	if from == to {
+		return i18n.NewError("err.already_in_status", i18n.Key("status."+to))
+	}
+	if !slices.Contains(transitions[from], to) {
+		return i18n.NewError("err.invalid_transition", i18n.Key("status."+from), i18n.Key("status."+to))
+	}
+	return nil
+}
+
+// findTodayTask は今日のリストの n 番目のタスクを返します。
+func findTodayTask(ctx context.Context, scope repository.Scope, n int) (repository.Task, error) {
+	tasks, err := GetTaskService(ctx, scope)
+	if err != nil {
+		return repository.Task{}, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	if len(tasks) == 0 {
+		return repository.Task{}, i18n.NewError("err.no_tasks")
+	}
+	if n < 0 || n >= len(tasks) {
+		return repository.Task{}, i18n.NewError("err.no_such_task")
+	}
+	return tasks[n], nil
+}
+
+// ChangeStatusService 今日のリストの n 番目のタスクを status に変える（done 以外。完了は CompleteTaskService）
+// 中止は削除と同じく、共有タスクでは作成者・担当者・管理者のみができます。
+func ChangeStatusService(ctx context.Context, scope repository.Scope, actor Actor, n int, status, reason string) (repository.Task, error) {
+	task, err := findTodayTask(ctx, scope, n)
+	if err != nil {
+		return task, err
+	}
+	if status == repository.StatusCancelled && !actor.CanModify(task) {
+		return task, i18n.NewError("err.shared_cancel")
+	}
+	if err := ValidateTransition(task.Status, status); err != nil {
+		return task, err
+	}
+	if err := transition(ctx, task.ID, actor.UserID, task.Status, status, reason); err != nil {
+		return task, err
+	}
+	task.Status = status
+	task.StatusReason = reason
+	return task, nil
+}
+
+// ReopenTaskService 完了・キャンセルしたタスクを未着手に戻す。タスクIDで指定し、今日以前のタスクも戻せる
+func ReopenTaskService(ctx context.Context, actor Actor, guildID string, taskID int) (repository.DatedTask, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return task, err
+	}
+	if !actor.CanModify(task.Task) {
+		return task, i18n.NewError("err.shared_edit")
+	}
+	if task.IsOpen() {
+		return task, i18n.NewError("err.not_closed", taskID)
+	}
+	if err := ValidateTransition(task.Status, repository.StatusTodo); err != nil {
+		return task, err
+	}
+	if err := transition(ctx, task.ID, actor.UserID, task.Status, repository.StatusTodo, ""); err != nil {
+		return task, err
+	}
+	task.Status = repository.StatusTodo
+	return task, nil
+}
+
+// transition はタスクの状態を変えて履歴に残します。
+func transition(ctx context.Context, taskID int, userID, from, to, reason string) error {
+	err := repository.TransitionTask(ctx, taskID, userID, from, to, reason)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return i18n.NewError("err.status_changed")
+	}
+	if err != nil {
+		return i18n.WrapError(err, "err.status_save")
+	}
+	return nil
+}
@@ -2,117 +2,252 @@ package service
 
 // タスク関連のCRUD処理
 import (
-	"fmt"
+	"context"
+	"errors"
 	"self-management-bot/client"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
 	"self-management-bot/repository"
 	"strings"
+	"time"
 )
 
-func AddTaskService(userID, title string, priorityID int) error {
-	return repository.AddTask(userID, title, priorityID)
+// AddTaskService タスクを追加する。estimateMinutes が 0 なら見積もりなし
+func AddTaskService(ctx context.Context, scope repository.Scope, userID, title string, priorityID, estimateMinutes int) error {
+	return repository.AddTask(ctx, scope, userID, "", title, priorityID, estimateMinutes)
+}
+
+// AssignTaskService requesterID から assigneeID にタスクを割り当てる
+// 個人リストの場合は担当者の個人リストに追加される
+func AssignTaskService(ctx context.Context, scope repository.Scope, requesterID, assigneeID, title string, priorityID, estimateMinutes int) error {
+	if !scope.IsShared() {
+		scope = repository.PersonalScope(assigneeID)
+	}
+	return repository.AddTask(ctx, scope, requesterID, assigneeID, title, priorityID, estimateMinutes)
+}
+
+// GetAssignedTaskService 自分が他のユーザに割り当てたタスクを取得
+func GetAssignedTaskService(ctx context.Context, userID string) ([]repository.Task, error) {
+	return repository.FindAssignedByUser(ctx, userID)
 }
 
 // GetTaskService 今日のタスクを取得
-func GetTaskService(userID string) ([]repository.Task, error) {
-	return repository.FindTaskByUserID(userID, "today")
+func GetTaskService(ctx context.Context, scope repository.Scope) ([]repository.Task, error) {
+	return repository.FindTasks(ctx, repository.TaskFilter{Scope: scope})
+}
+
+// ListPageSize は !list の1ページあたりの件数です。
+const ListPageSize = 10
+
+// TaskPage は絞り込んだタスクの1ページ分です。
+type TaskPage struct {
+	Tasks      []repository.Task
+	Numbers    map[int]int // タスクID → 今日のリストでの番号（!done などで指定する番号。無ければ含まない）
+	Page       int         // 0始まり
+	TotalPages int
+	Total      int
+	// FocusMinutes はタスクID → 集中した合計分数、FocusToday は今日集中した合計分数（個人のリストのみ）です。
+	FocusMinutes map[int]int
+	FocusToday   int
+}
+
+// ListTasksService filter に一致するタスクの page ページ目を取得する。page が範囲外なら最後のページに丸める
+func ListTasksService(ctx context.Context, filter repository.TaskFilter, page int) (TaskPage, error) {
+	total, err := repository.CountTasks(ctx, filter)
+	if err != nil {
+		return TaskPage{}, err
+	}
+	totalPages := max(1, (total+ListPageSize-1)/ListPageSize)
+	page = min(max(page, 0), totalPages-1)
+	filter.Limit = ListPageSize
+	filter.Offset = page * ListPageSize
+	tasks, err := repository.FindTasks(ctx, filter)
+	if err != nil {
+		return TaskPage{}, err
+	}
+	ids := make([]int, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	// 番号は今日のリスト（GetTaskService）の並びで振られるので、絞り込み結果にも同じ番号を表示する
+	numbers, err := repository.TaskNumbers(ctx, filter.Scope, ids)
+	if err != nil {
+		return TaskPage{}, err
+	}
+	focus, err := repository.FocusMinutesByTask(ctx, ids)
+	if err != nil {
+		return TaskPage{}, err
+	}
+	focusToday := 0
+	if !filter.Scope.IsShared() {
+		if focusToday, err = FocusMinutesTodayService(ctx, filter.Scope.UserID); err != nil {
+			return TaskPage{}, err
+		}
+	}
+	return TaskPage{Tasks: tasks, Numbers: numbers, Page: page, TotalPages: totalPages, Total: total,
+		FocusMinutes: focus, FocusToday: focusToday}, nil
+}
+
+// SearchLimit は !search で表示する最大件数です。
+const SearchLimit = 20
+
+// SearchTasksService scope のタスクを過去分も含めて検索する
+func SearchTasksService(ctx context.Context, scope repository.Scope, text string) ([]repository.DatedTask, error) {
+	return repository.SearchTasks(ctx, scope, text, SearchLimit)
 }
-func GetYesterdayTaskService(userID string) ([]repository.Task, error) {
-	return repository.FindTaskByUserID(userID, "yesterday")
+
+func GetYesterdayTaskService(ctx context.Context, userID string) ([]repository.Task, error) {
+	yesterday := time.Now().AddDate(0, 0, -1).Format(time.DateOnly)
+	return repository.FindTasks(ctx, repository.TaskFilter{
+		Scope:  repository.PersonalScope(userID),
+		Status: repository.StatusFilterAll,
+		Since:  yesterday,
+		Until:  yesterday,
+	})
 }
-func UpdateTaskService(userID string, TaskNumber int, title string, priorityID *int) error {
-	tasks, err := GetTaskService(userID)
+func UpdateTaskService(ctx context.Context, scope repository.Scope, actor Actor, TaskNumber int, title string, priorityID *int) error {
+	tasks, err := GetTaskService(ctx, scope)
 	// 内部エラー
 	if err != nil {
-		return fmt.Errorf("タスク取得に失敗: %w", err)
+		return i18n.WrapError(err, "err.fetch_tasks")
 	}
 	if len(tasks) == 0 {
-		return fmt.Errorf("タスクが1件も登録されていません")
+		return i18n.NewError("err.no_tasks")
 	}
 	if TaskNumber < 0 || TaskNumber >= len(tasks) {
-		return fmt.Errorf("指定されたタスク番号は存在しません")
+		return i18n.NewError("err.no_such_task")
+	}
+	if !actor.CanModify(tasks[TaskNumber]) {
+		return i18n.NewError("err.shared_edit")
 	}
-	return repository.UpdateTask(tasks[TaskNumber].ID, title, priorityID)
+	return repository.UpdateTask(ctx, tasks[TaskNumber].ID, title, priorityID)
 }
-func CompleteTaskService(userID string, DoneTaskNumber int) error {
-	tasks, err := GetTaskService(userID)
+
+// CompleteTaskService userID が完了したものとして記録し、完了したタスクを返す
+func CompleteTaskService(ctx context.Context, scope repository.Scope, userID string, DoneTaskNumber int) (repository.Task, error) {
+	tasks, err := GetTaskService(ctx, scope)
 	// 内部エラー
 	if err != nil {
-		return fmt.Errorf("タスク取得に失敗: %w", err)
+		return repository.Task{}, i18n.WrapError(err, "err.fetch_tasks")
 	}
 	if len(tasks) == 0 {
-		return fmt.Errorf("タスクが1件も登録されていません")
+		return repository.Task{}, i18n.NewError("err.no_tasks")
 	}
 	// タスク存在
 	if DoneTaskNumber < 0 || DoneTaskNumber >= len(tasks) {
-		return fmt.Errorf("指定されたタスク番号は存在しません")
+		return repository.Task{}, i18n.NewError("err.no_such_task")
+	}
+	task := tasks[DoneTaskNumber]
+	if err := ValidateTransition(task.Status, repository.StatusDone); err != nil {
+		return repository.Task{}, err
 	}
-	return repository.CompleteTask(tasks[DoneTaskNumber].ID)
+	if err := transition(ctx, task.ID, userID, task.Status, repository.StatusDone, ""); err != nil {
+		return repository.Task{}, err
+	}
+	task.Status = repository.StatusDone
+	task.CompletedBy = userID
+	return task, nil
 }
 
-func DeleteTaskService(userID string, DeleteTaskNumber int) error {
-	tasks, err := GetTaskService(userID)
+func DeleteTaskService(ctx context.Context, scope repository.Scope, actor Actor, DeleteTaskNumber int) error {
+	tasks, err := GetTaskService(ctx, scope)
 	// 内部エラー
 	if err != nil {
-		return fmt.Errorf("タスク取得に失敗: %w", err)
+		return i18n.WrapError(err, "err.fetch_tasks")
 	}
 	if len(tasks) == 0 {
-		return fmt.Errorf("タスクが1件も登録されていません")
+		return i18n.NewError("err.no_tasks")
 	}
 	// タスク存在
 	if DeleteTaskNumber < 0 || DeleteTaskNumber >= len(tasks) {
-		return fmt.Errorf("指定されたタスク番号は存在しません")
+		return i18n.NewError("err.no_such_task")
+	}
+	if !actor.CanModify(tasks[DeleteTaskNumber]) {
+		return i18n.NewError("err.shared_delete")
 	}
-	return repository.DeleteTask(tasks[DeleteTaskNumber].ID)
+	return repository.DeleteTask(ctx, tasks[DeleteTaskNumber].ID)
 }
 
-// ChatWithContext 今日のタスク状況について
-func ChatWithContext(userID, input string) (string, error) {
-	pending, err := repository.FindPendingTaskByUser(userID)
+// ChatWithContext 今日のタスク状況について。lang の言語で回答させる
+func ChatWithContext(ctx context.Context, userID, lang, input string) (string, error) {
+	pending, err := repository.FindPendingTaskByUser(ctx, userID)
+	if err != nil {
+		return "", i18n.WrapError(err, "err.chat_pending_fetch")
+	}
+	completed, err := repository.FindCompletedTodayTaskByUser(ctx, userID)
 	if err != nil {
-		return "❌ ユーザーのタスク取得に失敗しました(Pending)", err
+		return "", i18n.WrapError(err, "err.chat_completed_fetch")
 	}
-	completed, err := repository.FindCompletedTodayTaskByUser(userID)
+	var taskIDs []int
+	for _, t := range append(pending, completed...) {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	notes, err := repository.FindNotesByTasks(ctx, taskIDs)
 	if err != nil {
-		return "❌ ユーザーのタスク取得に失敗しました(Completed)", err
+		return "", i18n.WrapError(err, "err.chat_notes_fetch")
 	}
-	prompt := CreateChatPrompt(pending, completed, input)
-	res, err := client.GetGeminiResponse(prompt)
+	prompt := CreateChatPrompt(lang, pending, completed, notes, input)
+	res, err := client.GetGeminiResponse(ctx, prompt)
 	if err != nil {
-		return "❌ 応答に失敗しました(LLM)", err
+		return "", i18n.WrapError(err, "err.chat_llm")
 	}
 	return res, nil
 }
 
-// CreateChatPrompt 今日の完了状況をプロンプト化する
-func CreateChatPrompt(pending []repository.Task, completed []repository.Task, input string) string {
+// promptNotesPerTask, promptNoteLength はプロンプトに含めるメモの件数（新しい順）と1件あたりの最大文字数です。
+const (
+	promptNotesPerTask = 3
+	promptNoteLength   = 200
+)
+
+// writeTaskWithNotes はタスクのタイトルと、あればメモをプロンプトに書き出す
+func writeTaskWithNotes(prompt *strings.Builder, lang string, task repository.Task, notes []repository.TaskNote) {
+	prompt.WriteString("- " + task.Title + "\n")
+	if len(notes) > promptNotesPerTask {
+		notes = notes[len(notes)-promptNotesPerTask:]
+	}
+	for _, n := range notes {
+		body := []rune(strings.ReplaceAll(n.Body, "\n", " "))
+		if len(body) > promptNoteLength {
+			body = append(body[:promptNoteLength], '…')
+		}
+		prompt.WriteString(i18n.T(lang, "prompt.chat.note", string(body)))
+	}
+}
+
+// CreateChatPrompt 今日の完了状況をプロンプト化する。notes はタスクIDごとのメモ
+func CreateChatPrompt(lang string, pending []repository.Task, completed []repository.Task, notes map[int][]repository.TaskNote, input string) string {
 	var prompt strings.Builder
-	prompt.WriteString("あなたは，自己管理を支援するメンズコーチです．\n\n")
-	prompt.WriteString("【未完了のタスク】\n")
+	prompt.WriteString(i18n.T(lang, "prompt.chat.role"))
+	prompt.WriteString(i18n.T(lang, "prompt.chat.pending"))
 	if len(pending) == 0 {
-		prompt.WriteString("（未完了のタスクはありません）\n")
+		prompt.WriteString(i18n.T(lang, "prompt.chat.pending_none"))
 	} else {
 		for _, t := range pending {
-			prompt.WriteString("- " + t.Title + "\n")
+			writeTaskWithNotes(&prompt, lang, t, notes[t.ID])
 		}
 	}
-	prompt.WriteString("\n【最近完了したタスク】\n")
+	prompt.WriteString(i18n.T(lang, "prompt.chat.completed"))
 	if len(completed) == 0 {
-		prompt.WriteString("（完了したタスクはありません）\n")
+		prompt.WriteString(i18n.T(lang, "prompt.chat.completed_none"))
 	} else {
 		for _, t := range completed {
-			prompt.WriteString("- " + t.Title + "\n")
+			writeTaskWithNotes(&prompt, lang, t, notes[t.ID])
 		}
 	}
-	prompt.WriteString("\n【ユーザーの質問】\n")
+	prompt.WriteString(i18n.T(lang, "prompt.chat.question"))
 	prompt.WriteString(input + "\n")
-	prompt.WriteString("\n上記を踏まえてアドバイスせよ．")
+	prompt.WriteString(i18n.T(lang, "prompt.chat.instruction"))
+	prompt.WriteString("\n" + i18n.T(lang, "prompt.answer_language"))
 	return prompt.String()
 }
-func ResetTodayTasks(userID string) (int, error) {
-	return repository.DeleteTodayTasks(userID)
+
+// ResetTodayTasks scope のリストから今日のタスクを削除する
+func ResetTodayTasks(ctx context.Context, scope repository.Scope) (int, error) {
+	return repository.DeleteTodayTasks(ctx, scope)
 }
-func ResetAllTasks(userID string) (int, error) {
-	return repository.DeleteAllTasksByUser(userID)
+func ResetAllTasks(ctx context.Context, userID string) (int, error) {
+	return repository.DeleteAllTasksByUser(ctx, userID)
 }
 
 type ReminderMessage struct {
@@ -120,45 +255,68 @@ type ReminderMessage struct {
 	UserID  string // ユーザID
 }
 
-// FixedTimeReminder 定期リマインダ送信
-func FixedTimeReminder() ([]ReminderMessage, error) {
-	userInfo, err := repository.FindAllUser()
+// morningReminderUntil より前の時刻のリマインドを朝のリマインドとして、習慣の状態を載せます。
+const morningReminderUntil = 12
+
+// FixedTimeReminder 定期リマインダ送信。ユーザごとにメッセージを作り、1人で失敗しても他のユーザは続行する
+// 全員分が失敗したときだけエラーを返す
+func FixedTimeReminder(ctx context.Context, now time.Time) ([]ReminderMessage, error) {
+	logger := logging.From(ctx)
+	userIDs, err := repository.FindAllUser(ctx)
 	if err != nil {
-		fmt.Println("❌ ユーザ情報取得失敗:", err)
+		logger.Error("ユーザ情報取得失敗", "error", err)
 		return nil, err
 	}
-	// TODO 登録したすべてのユーザに送信するようにする
-	tasks, err := GetYesterdayTaskService(userInfo[0])
+	var messages []ReminderMessage
+	var errs []error
+	for _, userID := range userIDs {
+		msg, err := buildReminder(ctx, userID, now)
+		if err != nil {
+			logger.Error("リマインド作成失敗", "user_id", userID, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	if len(messages) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return messages, nil
+}
+
+// buildReminder は userID の昨日のタスクの状況からLLMにリマインドを書かせます。
+// 朝のリマインドには習慣の状態も含めます。
+func buildReminder(ctx context.Context, userID string, now time.Time) (ReminderMessage, error) {
+	logger := logging.From(ctx)
+	tasks, err := GetYesterdayTaskService(ctx, userID)
 	if err != nil {
-		fmt.Printf("❌ タスク取得失敗 userID=%s: %v\n", userInfo[0], err)
-		return nil, err
+		return ReminderMessage{}, err
 	}
+	// リマインドはDMなので、サーバー設定ではなくユーザの言語を使う
+	lang := UserLanguage(ctx, userID, i18n.Default)
 	var prompt strings.Builder
 	// プロンプト
-	prompt.WriteString("あなたは自己管理を支援するプロフェッショナルなコーチです。\n")
-	prompt.WriteString("昨日のタスクの実行状況をふまえ、今日を気持ちよくスタートできるように前向きで実用的なアドバイスを与えてください。\n")
-	prompt.WriteString("以下のルールに従ってください：\n")
-	prompt.WriteString("- 昨日の達成を簡潔に肯定的に振り返る（完了したタスクがあれば）\n")
-	prompt.WriteString("- 昨日未完了だったタスクがあれば、それをどう今日活かすか助言する\n")
-	prompt.WriteString("- アドバイスは1〜3個、シンプルかつ実行可能なものにする\n\n")
+	prompt.WriteString(i18n.T(lang, "prompt.reminder.role"))
+	prompt.WriteString(i18n.T(lang, "prompt.reminder.goal"))
+	prompt.WriteString(i18n.T(lang, "prompt.reminder.rules"))
 
 	// 昨日のタスクの整理
-	prompt.WriteString("【昨日のタスク状況】\n")
+	prompt.WriteString(i18n.T(lang, "prompt.reminder.status"))
 
 	hasCompleted := false
 	hasPending := false
 	// TODO Goに任せるんじゃなくてDB操作に任せたい（あとで）
 	for _, task := range tasks {
-		switch task.Status {
-		case "completed":
+		switch {
+		case task.Status == repository.StatusDone:
 			if !hasCompleted {
-				prompt.WriteString("▼完了したタスク：\n")
+				prompt.WriteString(i18n.T(lang, "prompt.reminder.completed"))
 				hasCompleted = true
 			}
 			prompt.WriteString("- " + task.Title + "\n")
-		case "pending":
+		case task.IsOpen():
 			if !hasPending {
-				prompt.WriteString("▼未完了のタスク：\n")
+				prompt.WriteString(i18n.T(lang, "prompt.reminder.pending"))
 				hasPending = true
 			}
 			prompt.WriteString("- " + task.Title + "\n")
@@ -166,23 +324,78 @@ func FixedTimeReminder() ([]ReminderMessage, error) {
 	}
 	// 何もタスクをこなしてない時
 	if !hasCompleted {
-		prompt.WriteString("▼完了したタスク：\n（完了したタスクはありません）\n")
+		prompt.WriteString(i18n.T(lang, "prompt.reminder.none_done"))
 	}
 	if !hasPending {
-		prompt.WriteString("▼未完了のタスク：\n（未完了のタスクはありません）\n")
+		prompt.WriteString(i18n.T(lang, "prompt.reminder.none_pending"))
 	}
-	prompt.WriteString("\nこの情報をふまえて、今日をポジティブに始めるためのメッセージを作成してください。\n")
-	res, err := client.GetGeminiResponse(prompt.String())
+	// 何日も持ち越している・何度も後回しにしているタスク
+	openTasks, err := repository.FindPendingTaskByUser(ctx, userID)
 	if err != nil {
-		fmt.Printf("❌ LLM応答失敗 userID=%s: %v\n", userInfo[0], err)
-		return nil, err
+		logger.Warn("持ち越しタスク取得失敗", "user_id", userID, "error", err)
+	}
+	hasChronic := false
+	for _, task := range openTasks {
+		if !IsChronic(task) {
+			continue
+		}
+		if !hasChronic {
+			prompt.WriteString(i18n.T(lang, "prompt.reminder.chronic"))
+			hasChronic = true
+		}
+		prompt.WriteString(i18n.T(lang, "prompt.reminder.chronic_item", task.Title, task.CarryOverDays+1, task.DeferCount))
+	}
+	if hasChronic {
+		prompt.WriteString(i18n.T(lang, "prompt.reminder.chronic_advice"))
+	}
+	yesterday := now.AddDate(0, 0, -1).Format(time.DateOnly)
+	if focused, err := repository.FocusMinutesOn(ctx, userID, yesterday); err != nil {
+		logger.Warn("集中時間取得失敗", "user_id", userID, "error", err)
+	} else if focused > 0 {
+		prompt.WriteString(i18n.T(lang, "prompt.reminder.focus", focused))
+	}
+	// 今日の見積もりの合計が1日に使える時間を超えていれば、AIに絞り込みを促してもらう
+	if plan, err := TodayPlanService(ctx, userID); err != nil {
+		logger.Warn("見積もり取得失敗", "user_id", userID, "error", err)
+	} else if plan.Estimated > 0 {
+		prompt.WriteString(i18n.T(lang, "prompt.reminder.estimate", plan.Estimated, plan.Available, plan.Unestimated))
+		if plan.Overcommitted() {
+			prompt.WriteString(i18n.T(lang, "prompt.reminder.overcommit", plan.Estimated-plan.Available))
+		}
+	}
+	// 朝は習慣を昨日やったかと連続日数をAIに伝え、メッセージの最後に今日の状態を付ける
+	var habits []HabitStatus
+	if now.Hour() < morningReminderUntil {
+		if habits, err = HabitStatusesService(ctx, userID, now); err != nil {
+			logger.Warn("習慣取得失敗", "user_id", userID, "error", err)
+		}
+	}
+	if len(habits) > 0 {
+		prompt.WriteString(i18n.T(lang, "prompt.reminder.habits"))
+		for _, h := range habits {
+			done := i18n.T(lang, "prompt.reminder.habit_missed")
+			if h.Yesterday {
+				done = i18n.T(lang, "prompt.reminder.habit_done")
+			}
+			prompt.WriteString(i18n.T(lang, "prompt.reminder.habit_item", h.Name, done, h.Current))
+		}
+	}
+	prompt.WriteString(i18n.T(lang, "prompt.reminder.instruction"))
+	prompt.WriteString(i18n.T(lang, "prompt.answer_language"))
+	res, err := client.GetGeminiResponse(ctx, prompt.String())
+	if err != nil {
+		return ReminderMessage{}, err
 	}
 
-	fmt.Println("✅ リマインド生成成功")
+	logger.Info("リマインド生成成功", "user_id", userID)
 
-	msg := ReminderMessage{
-		Content: res,
-		UserID:  userInfo[0],
+	if len(habits) > 0 {
+		var footer strings.Builder
+		footer.WriteString("\n\n" + i18n.T(lang, "habit.header"))
+		for _, h := range habits {
+			footer.WriteString(HabitLine(lang, h))
+		}
+		res += footer.String()
 	}
-	return []ReminderMessage{msg}, nil
+	return ReminderMessage{Content: res, UserID: userID}, nil
 }
@@ -0,0 +1,209 @@
+package service
+
+// タスクにかけた時間の計測（!track）と集計（!timesheet）
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"self-management-bot/db"
+	"self-management-bot/i18n"
+	"self-management-bot/repository"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxTrackMinutes は手入力で1回に記録できる最大の分数です。
+const MaxTrackMinutes = 24 * 60
+
+// tagPattern はタイトル中のタグ（"#仕事" など）です。"#12" のような数字だけのものはタスクIDなのでタグにしません。
+var tagPattern = regexp.MustCompile(`#([^\s#]+)`)
+
+// TaskTags はタイトルに含まれるタグを重複なく返します（"#" は付けません）。
+func TaskTags(title string) []string {
+	var tags []string
+	for _, m := range tagPattern.FindAllStringSubmatch(title, -1) {
+		if _, err := strconv.Atoi(m[1]); err == nil || slices.Contains(tags, m[1]) {
+			continue
+		}
+		tags = append(tags, m[1])
+	}
+	return tags
+}
+
+// ParseMinutes は "45m" / "1h30m" / "2h" / "90"（分）を分数にします。
+func ParseMinutes(text string) (int, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if n, err := strconv.Atoi(text); err == nil {
+		text = strconv.Itoa(n) + "m"
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil || d < time.Minute {
+		return 0, i18n.NewError("err.invalid_duration", text)
+	}
+	return int(d.Minutes()), nil
+}
+
+// TrackStart は始めた計測です。Stopped は代わりに止めた計測です（なければ nil）。
+type TrackStart struct {
+	Task    repository.DatedTask
+	Stopped *repository.TimeEntry
+}
+
+// StartTrackService actor がタスクにかける時間の計測を始める。別のタスクを計測中ならそちらを止めてから始める
+func StartTrackService(ctx context.Context, actor Actor, guildID string, taskID int, now time.Time) (TrackStart, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return TrackStart{}, err
+	}
+	if !task.IsOpen() {
+		return TrackStart{}, i18n.NewError("err.defer_closed", taskID)
+	}
+	started := TrackStart{Task: task}
+	running, err := repository.FindRunningTimeEntry(ctx, actor.UserID)
+	switch {
+	case err == nil && running.TaskID == taskID:
+		return started, i18n.NewError("err.track_already", taskID)
+	case err == nil:
+		// 止めるのと始めるのを1つのトランザクションで行い、片方だけ記録されることがないようにする
+		running.Minutes = elapsedMinutes(running, now)
+		if err := repository.SwitchTimeEntry(ctx, running.ID, running.Minutes, actor.UserID, taskID, db.TimeValue(now)); err != nil {
+			return started, i18n.WrapError(err, "err.track_save")
+		}
+		started.Stopped = &running
+		return started, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return started, i18n.WrapError(err, "err.track_save")
+	}
+	if err := repository.StartTimeEntry(ctx, actor.UserID, taskID, db.TimeValue(now)); err != nil {
+		return started, i18n.WrapError(err, "err.track_save")
+	}
+	return started, nil
+}
+
+// StopTrackService userID の計測を止め、始めてからの分数を記録する
+func StopTrackService(ctx context.Context, userID string, now time.Time) (repository.TimeEntry, error) {
+	running, err := repository.FindRunningTimeEntry(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return running, i18n.NewError("err.track_not_running")
+	}
+	if err != nil {
+		return running, i18n.WrapError(err, "err.track_save")
+	}
+	return stopTimeEntry(ctx, running, now)
+}
+
+// elapsedMinutes は計測中の記録を now に止めたときの分数です。1分未満でも1分とします。
+func elapsedMinutes(entry repository.TimeEntry, now time.Time) int {
+	started, err := db.ParseDateTimeText(entry.StartedAt)
+	if err != nil {
+		return 1
+	}
+	return max(1, int(now.Sub(started).Minutes()))
+}
+
+// stopTimeEntry は計測中の記録を止めます。
+func stopTimeEntry(ctx context.Context, entry repository.TimeEntry, now time.Time) (repository.TimeEntry, error) {
+	entry.Minutes = elapsedMinutes(entry, now)
+	if err := repository.StopTimeEntry(ctx, entry.ID, entry.Minutes, db.TimeValue(now)); err != nil {
+		return entry, i18n.WrapError(err, "err.track_save")
+	}
+	return entry, nil
+}
+
+// AddTrackService actor がタスクに minutes 分かけたことを手入力で記録する（今終わったものとして記録します）
+func AddTrackService(ctx context.Context, actor Actor, guildID string, taskID, minutes int, now time.Time) (repository.DatedTask, error) {
+	task, err := GetTaskByIDService(ctx, actor, guildID, taskID)
+	if err != nil {
+		return task, err
+	}
+	if minutes <= 0 || minutes > MaxTrackMinutes {
+		return task, i18n.NewError("err.track_minutes", MaxTrackMinutes/60)
+	}
+	startedAt := now.Add(-time.Duration(minutes) * time.Minute)
+	if err := repository.AddTimeEntry(ctx, actor.UserID, taskID, minutes, db.TimeValue(startedAt), db.TimeValue(now)); err != nil {
+		return task, i18n.WrapError(err, "err.track_save")
+	}
+	return task, nil
+}
+
+// TimesheetRow は集計の1行です。タスク別では TaskID も入ります。
+type TimesheetRow struct {
+	Label   string
+	TaskID  int
+	Minutes int
+}
+
+// Timesheet は since〜until（'YYYY-MM-DD'）にかけた時間の集計です。
+type Timesheet struct {
+	Since, Until string
+	Total        int
+	ByDay        []TimesheetRow // 日付順
+	ByTask       []TimesheetRow // 時間の長い順
+	ByTag        []TimesheetRow // 時間の長い順。タグのないタスクは Label が空
+	Entries      []repository.TimeEntry
+	// Running は計測中の記録です（集計には含めません）。
+	Running *repository.TimeEntry
+}
+
+// TimesheetService userID が今日（week なら今週の月曜から今日まで）にかけた時間を集計する
+func TimesheetService(ctx context.Context, userID string, week bool, now time.Time) (Timesheet, error) {
+	since := now
+	if week {
+		// 月曜始まり
+		since = now.AddDate(0, 0, -(int(now.Weekday())+6)%7)
+	}
+	sheet := Timesheet{Since: since.Format(time.DateOnly), Until: now.Format(time.DateOnly)}
+	entries, err := repository.FindTimeEntries(ctx, userID, sheet.Since, sheet.Until)
+	if err != nil {
+		return sheet, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	sheet.Entries = entries
+
+	byDay, byTask, byTag := map[string]int{}, map[int]int{}, map[string]int{}
+	titles := map[int]string{}
+	for _, e := range entries {
+		sheet.Total += e.Minutes
+		byDay[e.Day] += e.Minutes
+		byTask[e.TaskID] += e.Minutes
+		titles[e.TaskID] = e.Title
+		tags := TaskTags(e.Title)
+		if len(tags) == 0 {
+			tags = []string{""}
+		}
+		// 複数のタグが付いたタスクはそれぞれのタグに数える
+		for _, tag := range tags {
+			byTag[tag] += e.Minutes
+		}
+	}
+	for day, m := range byDay {
+		sheet.ByDay = append(sheet.ByDay, TimesheetRow{Label: day, Minutes: m})
+	}
+	slices.SortFunc(sheet.ByDay, func(a, b TimesheetRow) int { return cmp.Compare(a.Label, b.Label) })
+	for id, m := range byTask {
+		sheet.ByTask = append(sheet.ByTask, TimesheetRow{Label: titles[id], TaskID: id, Minutes: m})
+	}
+	sortByMinutes(sheet.ByTask)
+	for tag, m := range byTag {
+		sheet.ByTag = append(sheet.ByTag, TimesheetRow{Label: tag, Minutes: m})
+	}
+	sortByMinutes(sheet.ByTag)
+
+	running, err := repository.FindRunningTimeEntry(ctx, userID)
+	if err == nil {
+		sheet.Running = &running
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return sheet, i18n.WrapError(err, "err.fetch_tasks")
+	}
+	return sheet, nil
+}
+
+// sortByMinutes は時間の長い順（同じなら Label・TaskID 順）に並べます。
+func sortByMinutes(rows []TimesheetRow) {
+	slices.SortFunc(rows, func(a, b TimesheetRow) int {
+		return cmp.Or(cmp.Compare(b.Minutes, a.Minutes), cmp.Compare(a.Label, b.Label), cmp.Compare(a.TaskID, b.TaskID))
+	})
+}
@@ -0,0 +1,112 @@
+package service
+
+// ユーザごとの設定（表示言語・1日に使える時間）
+import (
+	"context"
+	"self-management-bot/i18n"
+	"self-management-bot/logging"
+	"self-management-bot/repository"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultAvailableMinutes は !hours で設定していないときの1日に使える分数です。
+const DefaultAvailableMinutes = 8 * 60
+
+// userSettingsCache はメッセージごとにDBを引かないためのキャッシュです（キー: userID）。
+var userSettingsCache sync.Map
+
+func findUserSettings(ctx context.Context, userID string) (repository.UserSettings, error) {
+	if cached, ok := userSettingsCache.Load(userID); ok {
+		return cached.(repository.UserSettings), nil
+	}
+	settings, err := repository.FindUserSettings(ctx, userID)
+	if err != nil {
+		return repository.UserSettings{UserID: userID}, err
+	}
+	userSettingsCache.Store(userID, settings)
+	return settings, nil
+}
+
+// UserLanguage はユーザに表示する言語を返します。
+// !lang で選んだ言語 → Discordのロケール → fallback（サーバー/チャンネルの設定）の順に使います。
+func UserLanguage(ctx context.Context, userID, fallback string) string {
+	settings, err := findUserSettings(ctx, userID)
+	if err != nil {
+		logging.From(ctx).Warn("ユーザ設定の取得に失敗", "error", err)
+	}
+	for _, lang := range []string{settings.Language.String, settings.DiscordLocale.String, fallback} {
+		if i18n.IsSupported(lang) {
+			return lang
+		}
+	}
+	return i18n.Default
+}
+
+// SetUserLanguage はユーザの表示言語を保存します。"reset" を指定すると未設定に戻します。
+func SetUserLanguage(ctx context.Context, userID, value string) error {
+	var stored interface{}
+	if value != "reset" {
+		if !i18n.IsSupported(value) {
+			return i18n.NewError("err.lang_invalid", strings.Join(i18n.Supported(), ", "))
+		}
+		stored = value
+	}
+	if err := repository.UpsertUserLanguage(ctx, userID, stored); err != nil {
+		return i18n.WrapError(err, "err.settings_save")
+	}
+	userSettingsCache.Delete(userID)
+	return nil
+}
+
+// RecordDiscordLocale はインタラクションで得たDiscordのロケールを保存します。
+// 対応していない言語や、既に同じ値が保存されている場合は何もしません。
+func RecordDiscordLocale(ctx context.Context, userID, locale string) error {
+	lang := i18n.FromDiscordLocale(locale)
+	if lang == "" {
+		return nil
+	}
+	if settings, err := findUserSettings(ctx, userID); err == nil && settings.DiscordLocale.String == lang {
+		return nil
+	}
+	if err := repository.UpsertDiscordLocale(ctx, userID, lang); err != nil {
+		return err
+	}
+	userSettingsCache.Delete(userID)
+	return nil
+}
+
+// AvailableMinutes はユーザが1日に使える分数を返します。
+func AvailableMinutes(ctx context.Context, userID string) int {
+	settings, err := findUserSettings(ctx, userID)
+	if err != nil {
+		logging.From(ctx).Warn("ユーザ設定の取得に失敗", "error", err)
+	}
+	if settings.AvailableMinutes.Valid {
+		return int(settings.AvailableMinutes.Int64)
+	}
+	return DefaultAvailableMinutes
+}
+
+// SetAvailableHours は1日に使える時間（"6"、"6.5"、"7h30m"）を保存し、分数を返します。"reset" を指定すると既定に戻します。
+func SetAvailableHours(ctx context.Context, userID, value string) (int, error) {
+	var stored interface{}
+	minutes := DefaultAvailableMinutes
+	if value != "reset" {
+		if hours, err := strconv.ParseFloat(value, 64); err == nil {
+			minutes = int(hours * 60)
+		} else if minutes, err = ParseMinutes(value); err != nil {
+			return 0, err
+		}
+		if minutes <= 0 || minutes > 24*60 {
+			return 0, i18n.NewError("err.hours_range")
+		}
+		stored = minutes
+	}
+	if err := repository.UpsertAvailableMinutes(ctx, userID, stored); err != nil {
+		return 0, i18n.WrapError(err, "err.settings_save")
+	}
+	userSettingsCache.Delete(userID)
+	return minutes, nil
+}